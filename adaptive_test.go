@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strings"
 	"testing"
-	"time"
 )
 
 func TestAdaptiveCapacity(t *testing.T) {
@@ -36,8 +35,6 @@ func TestAdaptiveCapacity(t *testing.T) {
 			ratio := float64(cap) / float64(len(matches))
 			fmt.Printf("%d\t%d\t\t%d\t%.2fx\n", i, cap, len(matches), ratio)
 		}
-
-		time.Sleep(1 * time.Millisecond)
 	}
 	fmt.Println("================================================================")
 }
@@ -0,0 +1,69 @@
+package flashtext
+
+import "sort"
+
+// alphabet maps the runes used by keywords to dense codes from 1, the most
+// frequent first. Every other rune shares code 0, from which no keyword
+// continues, so the automaton can go back to the root without a lookup.
+//
+// Runes of the Basic Multilingual Plane are found in 256-rune pages that are
+// only allocated when a keyword uses them: a Chinese dictionary of a few
+// thousand characters needs a few dozen pages, and a lookup is two array reads
+// instead of a map access.
+type alphabet struct {
+	low   [256]int32       // Latin-1 范围内的字符直接查表
+	pages [256]*[256]int32 // 其余 BMP 字符按高 8 位分页，未使用的页为 nil
+	high  map[rune]int32   // BMP 以外的字符和无效字节
+	size  int              // 编码的字符数，即最大编码
+}
+
+// newAlphabet assigns codes to the runes counted in count, which maps each
+// rune to the number of trie edges labelled with it.
+func newAlphabet(count map[rune]int) *alphabet {
+	runes := make([]rune, 0, len(count))
+	for char := range count {
+		runes = append(runes, char)
+	}
+	sort.Slice(runes, func(i, j int) bool {
+		if count[runes[i]] != count[runes[j]] {
+			return count[runes[i]] > count[runes[j]]
+		}
+		return runes[i] < runes[j]
+	})
+
+	al := &alphabet{high: make(map[rune]int32), size: len(runes)}
+	for i, char := range runes {
+		c := int32(i + 1)
+		switch {
+		case char >= 0 && char < 256:
+			al.low[char] = c
+		case char >= 0 && char < 0x10000:
+			page := al.pages[char>>8]
+			if page == nil {
+				page = new([256]int32)
+				al.pages[char>>8] = page
+			}
+			page[char&0xff] = c
+		default:
+			al.high[char] = c
+		}
+	}
+	return al
+}
+
+// code returns the code of r, 0 if no keyword uses it.
+func (al *alphabet) code(r rune) int32 {
+	if r >= 0 && r < 256 {
+		return al.low[r]
+	}
+	if r >= 0 && r < 0x10000 {
+		if page := al.pages[r>>8]; page != nil {
+			return page[r&0xff]
+		}
+		return 0
+	}
+	if len(al.high) == 0 {
+		return 0
+	}
+	return al.high[r]
+}
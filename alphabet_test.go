@@ -0,0 +1,28 @@
+package flashtext
+
+import "testing"
+
+// 只有关键词中的字符有编码，出现越多编码越小
+func TestAlphabet(t *testing.T) {
+	al := newAlphabet(map[rune]int{'a': 1, '中': 3, '😀': 2, byteRune(0xff): 1})
+	for _, tc := range []struct {
+		r    rune
+		code int32
+	}{
+		{'中', 1},
+		{'😀', 2},
+		{'a', 3},
+		{byteRune(0xff), 4},
+		{'b', 0},
+		{'文', 0}, // 与 '中' 同页
+		{'ア', 0}, // 未分配的页
+		{'😁', 0},
+	} {
+		if c := al.code(tc.r); c != tc.code {
+			t.Errorf("'%c': 期望 %d, 实际 %d", tc.r, tc.code, c)
+		}
+	}
+	if al.size != 4 {
+		t.Errorf("期望 4 个字符, 实际 %d", al.size)
+	}
+}
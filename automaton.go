@@ -0,0 +1,279 @@
+package flashtext
+
+import (
+	"math"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Automaton is a compiled AC automaton produced by KeywordProcessor.Build.
+// It never changes after Build, so it is safe for concurrent use and can be
+// swapped in and out of a HotProcessor while other goroutines are matching.
+type Automaton struct {
+	entries       []entry // 构建时的关键词快照
+	size          int     // 关键词数量
+	maxLen        int     // 最长关键词的 rune 长度
+	stats         *stats
+	caseSensitive bool
+	boundaries    *boundaries
+	matchKind     MatchKind
+	matchOrder    MatchOrder
+	invalidUTF8   InvalidUTF8Mode
+	backend       Backend
+
+	// 状态按广度优先编号 (RadixTrie 按链编号)，0 为根。在状态 s 结束的关键词为 out[s]、suffix[out[s]]……直到 -1，由长到短
+	depth    []int32    // 从根到该状态的字符数，RadixTrie 为 nil，见 depthOf
+	fail     []int32    // 失败转移
+	out      []int32    // 以该状态拼出的文本结尾的最长关键词 id，-1 表示没有
+	suffix   []int32    // 按关键词 id 索引：是该关键词后缀的最长关键词 id，-1 表示没有
+	alphabet *alphabet  // 关键词中字符的稠密编码
+	skip     *prefilter // 在根节点跳过不可能开始匹配的字节，nil 表示不使用
+
+	// ArrayTrie 和 RadixTrie 的转移，状态 t 的入边编码为 labels[t]，根节点按编码直接查表
+	labels   []int32
+	rootNext []int32
+
+	// ArrayTrie：状态 s 的子节点为 childStart[s] 到 childStart[s+1]-1，按编码排序
+	childStart []int32
+
+	// RadixTrie：nodeBlocks 标记节点，其余状态唯一的子节点是 s+1。第 k 个节点的深度为 nodeDepth[k]，
+	// 子节点编码和状态为 edgeLabels、edgeNext 的 [edgeStart[k], edgeStart[k+1])
+	nodeBlocks []nodeBlock
+	nodeDepth  []int32
+	edgeStart  []int32
+	edgeLabels []int32
+	edgeNext   []int32
+
+	da     *doubleArray // DoubleArray 的转移
+	dfa    []int32      // DFA 的转移表，每个状态一行，每行 stride 列
+	stride int
+}
+
+// Len returns the number of keywords in the automaton.
+func (a *Automaton) Len() int {
+	return a.size
+}
+
+// Backend returns how the transitions are stored. It differs from the backend
+// asked for with WithBackend when a DFA exceeded its memory limit.
+func (a *Automaton) Backend() Backend {
+	return a.backend
+}
+
+// Contains reports whether keyword is in the automaton,
+// ignoring case unless the automaton is case-sensitive.
+func (a *Automaton) Contains(keyword string) bool {
+	return a.lookup(keyword) >= 0
+}
+
+// GetKeyword returns the clean name of keyword and whether it is in the
+// automaton, ignoring case unless the automaton is case-sensitive.
+func (a *Automaton) GetKeyword(keyword string) (cleanName string, ok bool) {
+	id := a.lookup(keyword)
+	if id < 0 {
+		return "", false
+	}
+	return a.entries[id].cleanName, true
+}
+
+// Keywords returns an iterator over the keywords and their clean names in
+// lexicographic order of the trie. The keyword is reported as it was first added.
+func (a *Automaton) Keywords() func(yield func(keyword, cleanName string) bool) {
+	return func(yield func(keyword, cleanName string) bool) {
+		type key struct {
+			id    int
+			chars []rune
+		}
+		keys := make([]key, 0, a.size)
+		for id, e := range a.entries {
+			if e.length == 0 {
+				continue
+			}
+			chars := make([]rune, 0, e.length)
+			for i := 0; i < len(e.keyword); {
+				char, size := decodeRune(e.keyword[i:])
+				i += size
+				if !a.caseSensitive {
+					char = unicode.ToLower(char)
+				}
+				chars = append(chars, char)
+			}
+			keys = append(keys, key{id, chars})
+		}
+		// 按 Trie 路径上的字符逐个比较
+		sort.Slice(keys, func(i, j int) bool {
+			x, y := keys[i].chars, keys[j].chars
+			for k := 0; k < len(x) && k < len(y); k++ {
+				if x[k] != y[k] {
+					return x[k] < y[k]
+				}
+			}
+			return len(x) < len(y)
+		})
+		for _, k := range keys {
+			if !yield(a.entries[k.id].keyword, a.entries[k.id].cleanName) {
+				return
+			}
+		}
+	}
+}
+
+// walk feeds the sentence through the automaton and calls wf with the
+// byte offsets and the entry id of every match selected by kind.
+// It returns an *InvalidUTF8Error if it stopped at invalid UTF-8.
+func (a *Automaton) walk(sentence string, kind MatchKind, wf func(start, end, id int) bool) error {
+	var s scanner
+	s.reset(a, kind)
+	s.scan(sentence, 0, true, wf)
+	return s.err
+}
+
+// search is walk for the methods without an error result. Having no way to
+// report invalid UTF-8, they read an invalid byte as a character in
+// InvalidUTF8Fail mode too, as in InvalidUTF8AsByte.
+func (a *Automaton) search(sentence string, kind MatchKind, wf func(start, end, id int) bool) {
+	var s scanner
+	s.reset(a, kind)
+	if s.mode == InvalidUTF8Fail {
+		s.mode = InvalidUTF8AsByte
+	}
+	s.scan(sentence, 0, true, wf)
+}
+
+// FindAll calls fn for each match in text selected by the automaton's
+// MatchKind, in the default order of ExtractKeywords whatever the MatchOrder.
+// Return false from fn to stop. Text is decoded in place and nothing is
+// allocated per call, so it suits hot paths where a result slice is not needed.
+// The error is an *InvalidUTF8Error in InvalidUTF8Fail mode, nil otherwise.
+func (a *Automaton) FindAll(text string, fn func(Match) bool) error {
+	return a.walk(text, a.matchKind, func(start, end, id int) bool {
+		return fn(Match{
+			start:     start,
+			end:       end,
+			match:     text[start:end],
+			cleanName: a.entries[id].cleanName,
+			id:        id,
+		})
+	})
+}
+
+// FindAllBytes is like FindAll but searches a byte slice without copying it.
+// The MatchString of the reported matches shares memory with text: it must not
+// be used after text is modified, use strings.Clone to keep it.
+func (a *Automaton) FindAllBytes(text []byte, fn func(Match) bool) error {
+	return a.FindAll(bytesToString(text), fn)
+}
+
+// capEstimate predicts the number of matches in a text of n runes from the learned density.
+func (a *Automaton) capEstimate(n int) int {
+	return int(math.Ceil(float64(n) * a.stats.getDensity()))
+}
+
+// ExtractKeywords searches for keywords in a string.
+// It returns a slice of the matches selected by the automaton's MatchKind,
+// all overlapping matches by default. Matches are ordered by end offset, and
+// those ending at the same offset longest first, unless another MatchOrder
+// was chosen; the order does not depend on the order the keywords were added in.
+func (a *Automaton) ExtractKeywords(sentence string) []Match {
+	matches := a.extractKeywords(sentence, a.matchKind)
+	a.sort(matches)
+	return matches
+}
+
+func (a *Automaton) extractKeywords(sentence string, kind MatchKind) []Match {
+	// 优化: 预分配容量
+	runes := utf8.RuneCountInString(sentence)
+	if runes == 0 {
+		return nil
+	}
+	matches := a.appendMatches(make([]Match, 0, a.capEstimate(runes)), sentence, kind)
+	a.stats.add(len(matches), runes)
+	return matches
+}
+
+// AppendMatches appends the matches in text selected by the automaton's
+// MatchKind to dst and returns the extended slice, like strconv.AppendInt.
+// Reusing dst[:0] across calls, e.g. from a sync.Pool, allocates nothing once
+// the slice is large enough. The learned density is not used here; size dst
+// as fits the workload.
+func (a *Automaton) AppendMatches(dst []Match, text string) []Match {
+	n := len(dst)
+	dst = a.appendMatches(dst, text, a.matchKind)
+	a.sort(dst[n:])
+	return dst
+}
+
+func (a *Automaton) appendMatches(dst []Match, text string, kind MatchKind) []Match {
+	a.search(text, kind, func(start, end, id int) bool {
+		dst = append(dst, Match{
+			start:     start,
+			end:       end,
+			match:     text[start:end],
+			cleanName: a.entries[id].cleanName,
+			id:        id,
+		})
+		return true
+	})
+	return dst
+}
+
+// ExtractKeywordsFromBytes searches for keywords in a byte slice.
+// It returns a slice of the matches selected by the automaton's MatchKind.
+// The slice is scanned in place and only the text of the matches is copied,
+// so the matches do not share memory with sentence and do not keep it alive.
+func (a *Automaton) ExtractKeywordsFromBytes(sentence []byte) []Match {
+	matches := a.extractKeywords(bytesToString(sentence), a.matchKind)
+	detach(len(matches), func(i int) *Match { return &matches[i] }, sentence)
+	a.sort(matches)
+	return matches
+}
+
+// sort puts matches found in the default order into the automaton's MatchOrder.
+func (a *Automaton) sort(matches []Match) {
+	if a.sorted() {
+		return
+	}
+	a.sortMatches(len(matches), func(i int) *Match { return &matches[i] }, func(i, j int) {
+		matches[i], matches[j] = matches[j], matches[i]
+	})
+}
+
+// detach copies the text of the n matches returned by at out of text, so
+// that they no longer share memory with it. The matches must be in the
+// default order. Overlapping matches are merged into one span and each span
+// is copied once into a single allocation, whatever the distance between
+// the spans, so the copy is never longer than text.
+func detach(n int, at func(i int) *Match, text []byte) {
+	// spans 从右向左对每段重叠的匹配 [i, j) 及其覆盖的文本 [lo, hi) 调用 fn。
+	// 匹配按结束位置排序，前一个匹配在 lo 之后结束就与这一段重叠
+	spans := func(fn func(i, j, lo, hi int)) {
+		for j := n; j > 0; {
+			i, lo, hi := j-1, at(j-1).start, at(j-1).end
+			for i > 0 && at(i-1).end > lo {
+				i--
+				if start := at(i).start; start < lo {
+					lo = start
+				}
+			}
+			fn(i, j, lo, hi)
+			j = i
+		}
+	}
+	size := 0
+	spans(func(_, _, lo, hi int) { size += hi - lo })
+	if size == 0 {
+		return
+	}
+	buf := make([]byte, size)
+	copied := bytesToString(buf)
+	off := size
+	spans(func(i, j, lo, hi int) {
+		off -= hi - lo
+		copy(buf[off:], text[lo:hi]) // 每段只写一次，之后不再修改
+		for ; i < j; i++ {
+			m := at(i)
+			m.match = copied[off+m.start-lo : off+m.end-lo]
+		}
+	})
+}
@@ -1,6 +1,11 @@
 package flashtext
 
-import "testing"
+import (
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+)
 
 // 性能基准测试
 
@@ -155,6 +160,48 @@ func BenchmarkLongText(b *testing.B) {
 	}
 }
 
+// 匹配稀少的长文本，根节点可以跳过大部分字节
+func BenchmarkSparseText(b *testing.B) {
+	line := "2024-05-17 17:27:00 info request served in 12ms, status=200, path=/api/v1/users\n"
+	text := strings.Repeat(line, 800) + "2024-05-17 17:27:01 ERROR upstream timeout\n"
+	for _, tc := range []struct {
+		name     string
+		keywords []string
+		opts     []Option
+	}{
+		{"OneByte", []string{"ERROR"}, []Option{WithCaseSensitive()}},
+		{"Table", []string{"error", "fatal", "panic"}, nil},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			kp := NewKeywordProcessor(tc.opts...)
+			kp.AddKeywordsFromList(tc.keywords).Build()
+			defer kp.Close()
+
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				kp.FindAll(text, func(m Match) bool { return true })
+			}
+		})
+	}
+}
+
+// 回调接口 (不分配内存)
+func BenchmarkFindAll(b *testing.B) {
+	keywords := []string{"test", "golang", "performance", "data", "processing"}
+	text := strings.Repeat(`This is a test sentence with golang performance data processing. `, 80)
+
+	kp := NewKeywordProcessor()
+	kp.AddKeywordsFromList(keywords).Build()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		kp.FindAll(text, func(m Match) bool { return true })
+	}
+}
+
 // 测试大小写敏感 vs 不敏感的性能差异
 
 func BenchmarkCaseInsensitive(b *testing.B) {
@@ -230,3 +277,140 @@ func BenchmarkAddKeywords(b *testing.B) {
 		}
 	}
 }
+
+// 测试大词库下不同转移存储方式的内存和吞吐
+
+// largeDictionary 生成 n 个随机的中英文关键词和一段包含其中部分关键词的文本
+func largeDictionary(n int) ([]string, string) {
+	rnd := rand.New(rand.NewSource(1))
+	letters := []rune("abcdefghijklmnopqrstuvwxyz的一是不了人我在有他这中大来上国个到说们为子和你地出道也时年")
+	keywords := make([]string, n)
+	for i := range keywords {
+		rs := make([]rune, 4+rnd.Intn(12))
+		for j := range rs {
+			rs[j] = letters[rnd.Intn(len(letters))]
+		}
+		keywords[i] = string(rs)
+	}
+	var sb strings.Builder
+	for sb.Len() < 64*1024 {
+		if rnd.Intn(8) == 0 {
+			sb.WriteString(keywords[rnd.Intn(n)])
+		} else {
+			sb.WriteRune(letters[rnd.Intn(len(letters))])
+		}
+	}
+	return keywords, sb.String()
+}
+
+// cjkDictionary 生成 n 个由常用汉字组成的关键词和一段包含其中部分关键词的文本，
+// 字符集有两万多个字符
+func cjkDictionary(n int) ([]string, string) {
+	rnd := rand.New(rand.NewSource(2))
+	char := func() rune { return rune(0x4e00 + rnd.Intn(0x9fa5-0x4e00)) }
+	keywords := make([]string, n)
+	for i := range keywords {
+		rs := make([]rune, 2+rnd.Intn(5))
+		for j := range rs {
+			rs[j] = char()
+		}
+		keywords[i] = string(rs)
+	}
+	var sb strings.Builder
+	for sb.Len() < 64*1024 {
+		if rnd.Intn(8) == 0 {
+			sb.WriteString(keywords[rnd.Intn(n)])
+		} else {
+			sb.WriteRune(char())
+		}
+	}
+	return keywords, sb.String()
+}
+
+// longDictionary 生成 n 个 URL 和商品编号这样的长关键词，以及一段一半由关键词组成的文本
+func longDictionary(n int) ([]string, string) {
+	rnd := rand.New(rand.NewSource(3))
+	const letters = "abcdefghijklmnopqrstuvwxyz0123456789-"
+	word := func(n int) string {
+		bs := make([]byte, n)
+		for i := range bs {
+			bs[i] = letters[rnd.Intn(len(letters))]
+		}
+		return string(bs)
+	}
+	hosts := []string{"https://shop.example.com/products/", "https://cdn.example.net/assets/", "SKU-"}
+	keywords := make([]string, n)
+	for i := range keywords {
+		keywords[i] = hosts[rnd.Intn(len(hosts))] + word(16+rnd.Intn(32))
+	}
+	var sb strings.Builder
+	for sb.Len() < 64*1024 {
+		if rnd.Intn(2) == 0 {
+			sb.WriteString(keywords[rnd.Intn(n)])
+		} else {
+			// 与关键词前缀相同但之后不同的干扰文本
+			sb.WriteString(hosts[rnd.Intn(len(hosts))] + word(8))
+		}
+		sb.WriteByte(' ')
+	}
+	return keywords, sb.String()
+}
+
+func BenchmarkBackend(b *testing.B) {
+	benchmarkBackends(b, largeDictionary, 200000, ArrayTrie, RadixTrie, DoubleArray)
+}
+
+// 中小词库，DFA 转移表在默认内存预算之内
+func BenchmarkBackendHot(b *testing.B) {
+	benchmarkBackends(b, largeDictionary, 2000, ArrayTrie, RadixTrie, DoubleArray, DFA)
+}
+
+// 字符集很大的中文词库
+func BenchmarkBackendCJK(b *testing.B) {
+	benchmarkBackends(b, cjkDictionary, 100000, ArrayTrie, RadixTrie, DoubleArray)
+}
+
+// URL 等长关键词为主的词库
+func BenchmarkBackendLong(b *testing.B) {
+	benchmarkBackends(b, longDictionary, 200000, ArrayTrie, RadixTrie, DoubleArray)
+}
+
+func benchmarkBackends(b *testing.B, dictionary func(int) ([]string, string), n int, backends ...Backend) {
+	keywords, text := dictionary(n)
+	for _, backend := range backends {
+		b.Run(backend.String(), func(b *testing.B) {
+			// 只统计自动机，不含构建后保留的 Trie
+			var before, after runtime.MemStats
+			kp := NewKeywordProcessor(WithBackend(backend))
+			kp.AddKeywordsFromList(keywords)
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			kp.Build()
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+			defer kp.Close()
+
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				kp.FindAll(text, func(m Match) bool { return true })
+			}
+			b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/(1<<20), "MB-heap")
+		})
+	}
+}
+
+func BenchmarkBackendBuild(b *testing.B) {
+	keywords, _ := largeDictionary(200000)
+	for _, backend := range []Backend{ArrayTrie, DoubleArray} {
+		b.Run(backend.String(), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				kp := NewKeywordProcessor(WithBackend(backend))
+				kp.AddKeywordsFromList(keywords).Build()
+				kp.Close()
+			}
+		})
+	}
+}
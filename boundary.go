@@ -0,0 +1,59 @@
+package flashtext
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// boundaries decides whether a match is a whole word.
+type boundaries struct {
+	wordRunes map[rune]struct{} // 组成单词的字符，nil 表示使用默认规则
+}
+
+// continuousScripts are scripts written without spaces between words,
+// every rune of them is a word on its own.
+var continuousScripts = []*unicode.RangeTable{
+	unicode.Han,
+	unicode.Hiragana,
+	unicode.Katakana,
+	unicode.Thai,
+	unicode.Lao,
+	unicode.Khmer,
+	unicode.Myanmar,
+}
+
+// isWordRune reports whether r can continue a word.
+func (b *boundaries) isWordRune(r rune) bool {
+	if b.wordRunes != nil {
+		_, ok := b.wordRunes[r]
+		return ok
+	}
+	if r < utf8.RuneSelf {
+		return r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+	}
+	return (unicode.IsLetter(r) || unicode.IsDigit(r)) && !unicode.In(r, continuousScripts...)
+}
+
+// isBoundary reports whether a word boundary lies between the adjacent runes prev and next.
+func (b *boundaries) isBoundary(prev, next rune) bool {
+	return !b.isWordRune(prev) || !b.isWordRune(next)
+}
+
+// isWholeWord reports whether text[start:end] is delimited by word boundaries.
+// next is the rune following it, if hasNext.
+func (b *boundaries) isWholeWord(text string, start, end int, next rune, hasNext bool) bool {
+	if start > 0 {
+		prev, _ := utf8.DecodeLastRuneInString(text[:start])
+		first, _ := utf8.DecodeRuneInString(text[start:])
+		if !b.isBoundary(prev, first) {
+			return false
+		}
+	}
+	if hasNext {
+		last, _ := utf8.DecodeLastRuneInString(text[:end])
+		if !b.isBoundary(last, next) {
+			return false
+		}
+	}
+	return true
+}
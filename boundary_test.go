@@ -0,0 +1,54 @@
+package flashtext
+
+import "testing"
+
+// 完整单词匹配测试
+func TestWordBoundaries(t *testing.T) {
+	kp := NewKeywordProcessor(WithWordBoundaries())
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"cat", "c++", "手机", "iPhone", "东京"}).Build()
+
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"cat", []string{"cat"}},
+		{"a cat sat", []string{"cat"}},
+		{"category", nil},
+		{"concatenate", nil},
+		{"cat_food", nil},
+		{"cat, dog", []string{"cat"}},
+		{"I like c++x", []string{"c++"}},
+		{"买了iPhone手机", []string{"iPhone", "手机"}},
+		{"去东京旅游", []string{"东京"}},
+		{"iPhones", nil},
+	}
+	for _, tt := range tests {
+		matches := kp.ExtractKeywords(tt.text)
+		if len(matches) != len(tt.expected) {
+			t.Errorf("文本 '%s': 期望 %d 个匹配, 实际 %d 个", tt.text, len(tt.expected), len(matches))
+			continue
+		}
+		for i, m := range matches {
+			if m.MatchString() != tt.expected[i] {
+				t.Errorf("文本 '%s': 期望 '%s', 实际 '%s'", tt.text, tt.expected[i], m.MatchString())
+			}
+		}
+	}
+}
+
+// 自定义单词字符测试
+func TestNonWordBoundaries(t *testing.T) {
+	// '-' 也是单词的一部分
+	kp := NewKeywordProcessor(WithNonWordBoundaries("abcdefghijklmnopqrstuvwxyz-"))
+	defer kp.Close()
+	kp.AddKeyWord("cat").Build()
+
+	if n := len(kp.ExtractKeywords("cat-like")); n != 0 {
+		t.Errorf("期望 0 个匹配, 实际 %d 个", n)
+	}
+	// '_' 不在集合中，视为边界
+	if n := len(kp.ExtractKeywords("cat_like")); n != 1 {
+		t.Errorf("期望 1 个匹配, 实际 %d 个", n)
+	}
+}
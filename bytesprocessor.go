@@ -0,0 +1,193 @@
+package flashtext
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BytesProcessor finds byte signatures, e.g. magic numbers in binaries or
+// packet captures, which need not be valid UTF-8. Its automaton moves on bytes
+// instead of runes and is built by the same Build as KeywordProcessor.
+//
+// Patterns may contain single-byte wildcards, see AddHexPattern. The longest
+// run of literal bytes of a pattern is searched with the automaton, and the
+// rest of the pattern is checked around each occurrence of that run.
+// All methods are safe for concurrent use.
+type BytesProcessor struct {
+	kp       *KeywordProcessor // 以 rune(b) 作为字节的键，区分大小写
+	patterns int               // 已添加的模式数量，受 kp.mu 保护
+}
+
+// bytesPattern is a pattern attached to the entry of its literal anchor.
+type bytesPattern struct {
+	index  int     // 模式编号，按添加顺序从 0 开始
+	tokens []int16 // 每个位置的字节，-1 表示通配符
+	anchor int     // 锚点在模式中的偏移
+}
+
+// BytesMatch is an occurrence of a pattern in the data.
+type BytesMatch struct {
+	pattern int
+	start   int
+	end     int
+}
+
+// Pattern returns the number of the matched pattern, counting the patterns
+// in the order they were added from 0.
+func (m *BytesMatch) Pattern() int {
+	return m.pattern
+}
+
+func (m *BytesMatch) Start() int {
+	return m.start
+}
+
+func (m *BytesMatch) End() int {
+	return m.end
+}
+
+// NewBytesProcessor creates an empty processor for byte patterns.
+func NewBytesProcessor() *BytesProcessor {
+	return &BytesProcessor{kp: NewKeywordProcessor(WithCaseSensitive())}
+}
+
+// AddPattern adds a literal byte pattern and returns the processor for chaining.
+// Empty patterns are ignored. Matches report the pattern by its number, see
+// BytesMatch.Pattern.
+func (bp *BytesProcessor) AddPattern(pattern []byte) *BytesProcessor {
+	if len(pattern) == 0 {
+		return bp
+	}
+	tokens := make([]int16, len(pattern))
+	for i, b := range pattern {
+		tokens[i] = int16(b)
+	}
+	bp.add(tokens)
+	return bp
+}
+
+// AddHexPattern adds a pattern written in hex, two digits per byte, with "??"
+// standing for any byte, e.g. "4D 5A ?? 00". Whitespace is ignored. The pattern
+// must hold at least one literal byte.
+func (bp *BytesProcessor) AddHexPattern(pattern string) error {
+	digits := strings.Join(strings.Fields(pattern), "")
+	if len(digits) == 0 || len(digits)%2 != 0 {
+		return fmt.Errorf("flashtext: invalid hex pattern %q: odd or zero number of digits", pattern)
+	}
+	tokens := make([]int16, len(digits)/2)
+	literal := false
+	for i := range tokens {
+		pair := digits[2*i : 2*i+2]
+		if pair == "??" {
+			tokens[i] = -1
+			continue
+		}
+		b, err := hex.DecodeString(pair)
+		if err != nil {
+			return fmt.Errorf("flashtext: invalid hex pattern %q: bad byte %q", pattern, pair)
+		}
+		tokens[i] = int16(b[0])
+		literal = true
+	}
+	if !literal {
+		return fmt.Errorf("flashtext: invalid hex pattern %q: no literal byte", pattern)
+	}
+	bp.add(tokens)
+	return nil
+}
+
+// add registers the pattern under its longest literal run.
+func (bp *BytesProcessor) add(tokens []int16) {
+	anchor, length := 0, 0
+	for i := 0; i < len(tokens); {
+		if tokens[i] < 0 {
+			i++
+			continue
+		}
+		j := i
+		for j < len(tokens) && tokens[j] >= 0 {
+			j++
+		}
+		if j-i > length {
+			anchor, length = i, j-i
+		}
+		i = j
+	}
+	// 字节 b 以 rune(b) 存入 Trie
+	keys := make([]rune, length)
+	for i := range keys {
+		keys[i] = rune(tokens[anchor+i])
+	}
+	keyword := string(keys)
+
+	bp.kp.mu.Lock()
+	defer bp.kp.mu.Unlock()
+	p := &bytesPattern{index: bp.patterns, tokens: tokens, anchor: anchor}
+	bp.patterns++
+	// 共用锚点的模式挂在同一个关键词上，复制后追加以免影响已构建的自动机
+	var shared []*bytesPattern
+	if id := bp.kp.lookup(keyword); id >= 0 {
+		shared = bp.kp.entries[id].payload.([]*bytesPattern)
+	}
+	bp.kp.setItem(keyword, "", append(shared[:len(shared):len(shared)], p))
+}
+
+// Len returns the number of patterns added.
+func (bp *BytesProcessor) Len() int {
+	bp.kp.mu.Lock()
+	defer bp.kp.mu.Unlock()
+	return bp.patterns
+}
+
+// Build compiles the patterns.
+// Matching builds the processor automatically when patterns were added.
+func (bp *BytesProcessor) Build() {
+	bp.kp.Build()
+}
+
+// ExtractPatterns returns every occurrence of every pattern in data,
+// overlapping ones included, ordered by start offset and then by pattern number.
+func (bp *BytesProcessor) ExtractPatterns(data []byte) []BytesMatch {
+	a := bp.kp.automaton()
+	var matches []BytesMatch
+	state := int32(0)
+	for i, b := range data {
+		state = a.next(state, rune(b))
+		for id := a.out[state]; id >= 0; id = a.suffix[id] {
+			at := i + 1 - a.entries[id].length
+			for _, p := range a.entries[id].payload.([]*bytesPattern) {
+				if start, ok := p.match(data, at); ok {
+					matches = append(matches, BytesMatch{pattern: p.index, start: start, end: start + len(p.tokens)})
+				}
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].pattern < matches[j].pattern
+	})
+	return matches
+}
+
+// match checks the whole pattern around its anchor found at offset at,
+// and returns where the pattern starts.
+func (p *bytesPattern) match(data []byte, at int) (int, bool) {
+	start := at - p.anchor
+	if start < 0 || start+len(p.tokens) > len(data) {
+		return 0, false
+	}
+	for i, t := range p.tokens {
+		if t >= 0 && data[start+i] != byte(t) {
+			return 0, false
+		}
+	}
+	return start, true
+}
+
+func (bp *BytesProcessor) Close() {
+	bp.kp.Close()
+}
@@ -0,0 +1,63 @@
+package flashtext
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func bytesMatchStrings(matches []BytesMatch) string {
+	parts := make([]string, len(matches))
+	for i, m := range matches {
+		parts[i] = fmt.Sprintf("%d[%d:%d]", m.Pattern(), m.Start(), m.End())
+	}
+	return strings.Join(parts, " ")
+}
+
+// 字节模式匹配，包括非 UTF-8 数据和通配符
+func TestBytesProcessor(t *testing.T) {
+	bp := NewBytesProcessor()
+	defer bp.Close()
+	bp.AddPattern([]byte{0x4D, 0x5A}) // 0
+	for _, p := range []string{
+		"4D 5A ?? 00",    // 1
+		"?? ff fe",       // 2
+		"5a90??",         // 3
+		"FF FE ?? ?? 01", // 4
+	} {
+		if err := bp.AddHexPattern(p); err != nil {
+			t.Fatalf("添加模式 %q 失败: %v", p, err)
+		}
+	}
+	bp.AddPattern([]byte{0xFF, 0xFE}) // 5，与模式 2、4 共用锚点
+	bp.Build()
+
+	data := []byte{0x4D, 0x5A, 0x90, 0x00, 0xFF, 0xFE, 0x00, 0x00, 0x01, 0xFF, 0xFE}
+	want := "0[0:2] 1[0:4] 3[1:4] 2[3:6] 4[4:9] 5[4:6] 2[8:11] 5[9:11]"
+	if got := bytesMatchStrings(bp.ExtractPatterns(data)); got != want {
+		t.Errorf("期望 '%s', 实际 '%s'", want, got)
+	}
+	if bp.Len() != 6 {
+		t.Errorf("期望 6 个模式, 实际 %d", bp.Len())
+	}
+
+	// 添加模式后自动重新构建
+	bp.AddPattern([]byte{0x00, 0x00})
+	if got := bytesMatchStrings(bp.ExtractPatterns(data[5:9])); got != "6[1:3]" {
+		t.Errorf("期望 '6[1:3]', 实际 '%s'", got)
+	}
+}
+
+// 非法的十六进制模式
+func TestAddHexPatternInvalid(t *testing.T) {
+	bp := NewBytesProcessor()
+	defer bp.Close()
+	for _, p := range []string{"", "4", "4G", "?? ??", "4D 5"} {
+		if err := bp.AddHexPattern(p); err == nil {
+			t.Errorf("模式 %q 应该报错", p)
+		}
+	}
+	if bp.Len() != 0 {
+		t.Errorf("非法模式不应被添加, 实际 %d 个", bp.Len())
+	}
+}
@@ -0,0 +1,326 @@
+package flashtext
+
+import "unicode"
+
+// Backend selects how a compiled Automaton stores the transitions of its trie.
+// It changes memory use and speed, never the matches.
+//
+// Whatever the backend, Build first maps the runes used by the keywords to
+// dense codes, and a rune that no keyword uses takes the automaton back to the
+// root without looking at any transition.
+type Backend int
+
+const (
+	// ArrayTrie keeps the children of every state in an array sorted by code,
+	// searched in a few comparisons, and those of the root in a table indexed
+	// by code.
+	ArrayTrie Backend = iota
+	// DoubleArray packs all transitions into a double array (base/check):
+	// a transition is two array reads instead of a search. That pays off on
+	// small dictionaries whose arrays stay in the cache, and on states with
+	// many children such as those of CJK dictionaries. On other large ones
+	// the two reads land far apart, so it is slower than ArrayTrie, up to
+	// three times on long keywords. The holes left between the packed rows
+	// make it take some 10 to 20% more memory.
+	DoubleArray
+	// DFA precomputes the transition of every state on every rune used by the
+	// keywords, so each input rune is exactly one table lookup and failure
+	// links are never followed while matching. The table takes
+	// states*(distinct runes+1)*4 bytes; if that exceeds the limit set by
+	// WithDFAMemoryLimit, Build falls back to ArrayTrie.
+	DFA
+	// RadixTrie compresses the runs of states with a single child, as spelled
+	// by long keywords like URLs or product codes, into edges labelled with a
+	// run of codes. Only the nodes where runs start or end keep a depth and a
+	// sorted child table; a state inside a run keeps its code, failure link
+	// and output, 12 bytes against 20 for ArrayTrie, so that mismatches
+	// inside a run are handled exactly as in the other backends. Moving along
+	// a run is comparing the next code. Dictionaries of short, branching
+	// keywords have few runs to compress, and there finding the child of a
+	// node makes it somewhat slower than ArrayTrie.
+	RadixTrie
+)
+
+func (b Backend) String() string {
+	switch b {
+	case ArrayTrie:
+		return "ArrayTrie"
+	case DoubleArray:
+		return "DoubleArray"
+	case DFA:
+		return "DFA"
+	case RadixTrie:
+		return "RadixTrie"
+	default:
+		return "Backend(?)"
+	}
+}
+
+// compile numbers the states of the trie below root, in breadth-first order
+// with the children of a state in code order, or for RadixTrie run by run,
+// and fills the state arrays of a: alphabet, prefilter, depths, failure
+// links, output links and the transitions of the chosen backend. A DFA whose
+// table would exceed dfaLimit bytes is compiled as ArrayTrie.
+// root is only read.
+func (a *Automaton) compile(root *Node, dfaLimit int) {
+	// 统计每个字符所在的边数，出现越多编码越小
+	count := make(map[rune]int)
+	for stack := []*Node{root}; len(stack) > 0; {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for char, child := range node.children {
+			count[char]++
+			stack = append(stack, child)
+		}
+	}
+	al := newAlphabet(count)
+	a.alphabet = al
+	firsts := make([]rune, 0, len(root.children))
+	for char := range root.children {
+		firsts = append(firsts, char)
+	}
+	a.skip = newPrefilter(firsts, a.caseSensitive, a.invalidUTF8)
+
+	var nodes []*Node
+	var parents, order []int32
+	if a.backend == RadixTrie {
+		nodes, parents, order = a.numberRadix(root)
+	} else {
+		nodes, parents = a.numberBFS(root)
+	}
+	n := len(nodes)
+
+	// 按广度优先的顺序处理，父状态和失败状态更浅，都已确定
+	a.depth = make([]int32, n)
+	a.fail = make([]int32, n)
+	a.out = make([]int32, n)
+	a.suffix = make([]int32, len(a.entries))
+	a.out[0] = -1
+	for i := 1; i < n; i++ {
+		s := int32(i)
+		if order != nil {
+			s = order[i]
+		}
+		p, c := parents[s], a.labels[s]
+		a.depth[s] = a.depth[p] + 1
+		if p != 0 {
+			for f := a.fail[p]; ; f = a.fail[f] {
+				if t := a.compiledChild(f, c); t >= 0 {
+					a.fail[s] = t
+					break
+				}
+				if f == 0 {
+					break
+				}
+			}
+		}
+		// 每个状态只记录最长的关键词，更短的由关键词各自的 suffix 串起来
+		f := a.fail[s]
+		if id := int32(nodes[s].keywordAt(a.entries)); id >= 0 {
+			a.out[s] = id
+			a.suffix[id] = a.out[f]
+		} else {
+			a.out[s] = a.out[f]
+		}
+	}
+
+	switch a.backend {
+	case DFA:
+		if size := dfaSize(n, al.size+1); size >= 0 && size <= dfaLimit {
+			a.stride = al.size + 1
+			a.dfa = newDFA(a.stride, a.childStart, a.labels, a.fail)
+			a.childStart, a.labels = nil, nil
+			return
+		}
+		// 超出内存预算时退回到默认的有序数组和失败转移
+		a.backend = ArrayTrie
+	case DoubleArray:
+		a.da = newDoubleArray(a.childStart, a.labels)
+		a.childStart, a.labels = nil, nil
+		return
+	}
+	// 根的转移最频繁，直接按编码查表
+	a.rootNext = make([]int32, al.size+1)
+	for c := int32(1); c <= int32(al.size); c++ {
+		if t := a.compiledChild(0, c); t >= 0 {
+			a.rootNext[c] = t
+		}
+	}
+	if a.backend == RadixTrie {
+		// 深度由节点推算，链中的状态不保存
+		a.depth = nil
+	}
+}
+
+// edge is a child of a trie node and the code of its rune.
+type edge struct {
+	code int32
+	node *Node
+}
+
+// sortedEdges appends the children of node to edges[:0] in code order.
+func (al *alphabet) sortedEdges(edges []edge, node *Node) []edge {
+	edges = edges[:0]
+	for char, child := range node.children {
+		edges = append(edges, edge{al.code(char), child})
+	}
+	// 子节点通常很少，插入排序即可
+	for i := 1; i < len(edges); i++ {
+		for j := i; j > 0 && edges[j].code < edges[j-1].code; j-- {
+			edges[j], edges[j-1] = edges[j-1], edges[j]
+		}
+	}
+	return edges
+}
+
+// numberBFS numbers the states in breadth-first order, so that the children
+// of each state are consecutive, and fills childStart and labels. It returns
+// the nodes and the parents of the states.
+func (a *Automaton) numberBFS(root *Node) (nodes []*Node, parents []int32) {
+	nodes = []*Node{root}
+	parents = []int32{0}
+	a.childStart = []int32{1}
+	a.labels = []int32{0}
+	var edges []edge
+	for s := 0; s < len(nodes); s++ {
+		edges = a.alphabet.sortedEdges(edges, nodes[s])
+		for _, e := range edges {
+			nodes = append(nodes, e.node)
+			parents = append(parents, int32(s))
+			a.labels = append(a.labels, e.code)
+		}
+		a.childStart = append(a.childStart, int32(len(nodes)))
+	}
+	return nodes, parents
+}
+
+// compiledChild returns the child of state s on code c while compiling,
+// before the transitions of DFA and DoubleArray exist.
+func (a *Automaton) compiledChild(s, c int32) int32 {
+	if a.backend == RadixTrie {
+		return a.radixChild(s, c)
+	}
+	return a.arrayChild(s, c)
+}
+
+// arrayChild returns the child of state s on code c in the sorted child
+// arrays, or -1.
+func (a *Automaton) arrayChild(s, c int32) int32 {
+	return searchCode(a.labels, a.childStart[s], a.childStart[s+1], c)
+}
+
+// searchCode returns the index of c in codes[lo:hi], sorted, or -1.
+func searchCode(codes []int32, lo, hi, c int32) int32 {
+	for hi-lo > 8 {
+		mid := int32(uint32(lo+hi) >> 1)
+		if codes[mid] < c {
+			lo = mid + 1
+		} else {
+			hi = mid + 1 // codes[mid] 可能就是 c
+		}
+	}
+	for i := lo; i < hi && codes[i] <= c; i++ {
+		if codes[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// child returns the child of state s on the lowercased rune r, or -1.
+func (a *Automaton) child(s int32, r rune) int32 {
+	c := a.alphabet.code(r)
+	if c == 0 {
+		return -1
+	}
+	switch a.backend {
+	case DoubleArray:
+		return a.da.child(s, c)
+	case RadixTrie:
+		return a.radixChild(s, c)
+	case DFA:
+		// 表中也有经失败链得到的转移，只有深度加一的才是子节点
+		t := a.dfa[int(s)*a.stride+int(c)]
+		if t == 0 || a.depth[t] != a.depth[s]+1 {
+			return -1
+		}
+		return t
+	}
+	return a.arrayChild(s, c)
+}
+
+// next returns the state reached from state s on rune r.
+func (a *Automaton) next(s int32, r rune) int32 {
+	if !a.caseSensitive {
+		r = unicode.ToLower(r)
+	}
+	c := a.alphabet.code(r)
+	if c == 0 {
+		return 0 // 没有关键词包含该字符
+	}
+	switch a.backend {
+	case DFA:
+		return a.dfa[int(s)*a.stride+int(c)]
+	case DoubleArray:
+		for {
+			if t := a.da.child(s, c); t >= 0 {
+				return t
+			}
+			if s == 0 {
+				return 0
+			}
+			s = a.fail[s]
+		}
+	case RadixTrie:
+		for ; s != 0; s = a.fail[s] {
+			if t := a.radixChild(s, c); t >= 0 {
+				return t
+			}
+		}
+		return a.rootNext[c]
+	}
+	for ; s != 0; s = a.fail[s] {
+		if t := a.arrayChild(s, c); t >= 0 {
+			return t
+		}
+	}
+	return a.rootNext[c]
+}
+
+// find returns the state spelling keyword from the root, or -1.
+func (a *Automaton) find(keyword string) int32 {
+	s := int32(0)
+	for i := 0; i < len(keyword) && s >= 0; {
+		char, size := decodeRune(keyword[i:])
+		i += size
+		if !a.caseSensitive {
+			char = unicode.ToLower(char)
+		}
+		s = a.child(s, char)
+	}
+	return s
+}
+
+// lookup returns the id of keyword, or -1 if it is not in the automaton.
+func (a *Automaton) lookup(keyword string) int {
+	if len(keyword) == 0 {
+		return -1
+	}
+	s := a.find(keyword)
+	if s < 0 {
+		return -1
+	}
+	// 最长的输出正好是该状态拼出的文本时才是关键词本身
+	if id := a.out[s]; id >= 0 && a.entries[id].length == int(a.depthOf(s)) {
+		return int(id)
+	}
+	return -1
+}
+
+// depthOf returns the number of runes spelled by state s.
+func (a *Automaton) depthOf(s int32) int32 {
+	if a.depth == nil {
+		return a.radixDepth(s)
+	}
+	return a.depth[s]
+}
@@ -0,0 +1,43 @@
+package flashtext
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// 同一结尾的匹配由长到短，与添加顺序无关
+func TestMatchOrder(t *testing.T) {
+	keywords := []string{"a", "aa", "aaa", "ba", "cba", "b", "c"}
+	want := "a[0:1] c[1:2] b[2:3] cba[1:4] ba[2:4] a[3:4] aa[3:5] a[4:5] aaa[3:6] aa[4:6] a[5:6]"
+	rnd := rand.New(rand.NewSource(6))
+	for round := 0; round < 10; round++ {
+		rnd.Shuffle(len(keywords), func(i, j int) { keywords[i], keywords[j] = keywords[j], keywords[i] })
+		kp := NewKeywordProcessor()
+		if s := matchStrings(kp.AddKeywordsFromList(keywords).ExtractKeywords("acbaaa")); s != want {
+			t.Fatalf("关键词 %v: 期望 '%s', 实际 '%s'", keywords, want, s)
+		}
+		kp.Close()
+	}
+}
+
+// 互为后缀的关键词只保存自身的输出，内存与状态数成正比
+func TestOutputLinks(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	for i := 1; i <= 1000; i++ {
+		kp.AddKeyWord(strings.Repeat("a", i))
+	}
+	a := kp.Build()
+	if len(a.out) != 1001 || len(a.suffix) != 1000 {
+		t.Errorf("期望 1001 个状态、1000 个关键词, 实际 %d、%d", len(a.out), len(a.suffix))
+	}
+	n := 0
+	a.FindAll(strings.Repeat("a", 1000), func(m Match) bool {
+		n++
+		return true
+	})
+	if n != 1000*1001/2 {
+		t.Errorf("期望 %d 个匹配, 实际 %d", 1000*1001/2, n)
+	}
+}
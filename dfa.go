@@ -0,0 +1,38 @@
+package flashtext
+
+import "math"
+
+// DefaultDFAMemoryLimit is the memory budget of the DFA backend in bytes when
+// WithDFAMemoryLimit is not given.
+const DefaultDFAMemoryLimit = 64 << 20
+
+// dfaSize returns the bytes taken by the transition table of n states with
+// stride columns each, or -1 if it would not fit in an int.
+func dfaSize(n, stride int) int {
+	if n > 0 && stride > math.MaxInt/4/n {
+		return -1
+	}
+	return n * stride * 4
+}
+
+// newDFA fills the complete transition table of the breadth-first numbered
+// trie whose children of state s are the states childStart[s] to
+// childStart[s+1]-1, state t being entered on code labels[t]. The transition
+// of state s on code c is table[s*stride+c]; code 0, the runes no keyword
+// uses, always leads to the root.
+func newDFA(stride int, childStart, labels, fail []int32) []int32 {
+	n := len(childStart) - 1
+	table := make([]int32, n*stride)
+	for s := 0; s < n; s++ {
+		row := table[s*stride : (s+1)*stride]
+		// 失败状态编号更小，它的行已经填好；没有子节点的字符沿用失败状态的转移
+		if s > 0 {
+			f := int(fail[s])
+			copy(row, table[f*stride:(f+1)*stride])
+		}
+		for t := childStart[s]; t < childStart[s+1]; t++ {
+			row[labels[t]] = t
+		}
+	}
+	return table
+}
@@ -0,0 +1,29 @@
+package flashtext
+
+import "testing"
+
+// 转移表超出内存预算时退回默认的 ArrayTrie
+func TestDFAMemoryLimit(t *testing.T) {
+	keywords := []string{"he", "she", "his", "hers"}
+	text := "ushers his"
+	want := "she[1:4] he[2:4] hers[2:6] his[7:10]"
+
+	// 10 个状态，5 个字符，每个状态一行 6 列
+	for _, tc := range []struct {
+		limit   int
+		backend Backend
+	}{
+		{10 * 6 * 4, DFA},
+		{10*6*4 - 1, ArrayTrie},
+	} {
+		kp := NewKeywordProcessor(WithBackend(DFA), WithDFAMemoryLimit(tc.limit))
+		a := kp.AddKeywordsFromList(keywords).Build()
+		if a.Backend() != tc.backend {
+			t.Errorf("预算 %d: 期望 %v, 实际 %v", tc.limit, tc.backend, a.Backend())
+		}
+		if s := matchStrings(a.ExtractKeywords(text)); s != want {
+			t.Errorf("预算 %d: 期望 '%s', 实际 '%s'", tc.limit, want, s)
+		}
+		kp.Close()
+	}
+}
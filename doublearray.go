@@ -0,0 +1,91 @@
+package flashtext
+
+// doubleArray stores the transitions of a trie in three flat arrays. The child
+// of state s on code c lives in slot t = base[s]+c if check[t] == s, and its
+// state number is next[t]. Slots are shared by all states, so the arrays stay
+// nearly full however sparse the alphabet use of each state is.
+type doubleArray struct {
+	base  []int32 // 按状态编号
+	check []int32 // 按槽位，占用该槽位的父状态，-1 表示空闲
+	next  []int32 // 按槽位，子状态编号
+}
+
+// newDoubleArray packs the transitions of the breadth-first numbered trie
+// whose children of state s are the states childStart[s] to childStart[s+1]-1,
+// state t being entered on code labels[t].
+func newDoubleArray(childStart, labels []int32) *doubleArray {
+	n := len(childStart) - 1
+	da := &doubleArray{base: make([]int32, n)}
+	var skip []int32 // 已占用槽位指向其后的槽位，用于快速找到下一个空闲槽位
+	grow := func(size int) {
+		for len(da.check) < size {
+			skip = append(skip, int32(len(da.check)+1))
+			da.check = append(da.check, -1)
+			da.next = append(da.next, 0)
+		}
+	}
+	// firstFree returns the first free slot at or after p.
+	firstFree := func(p int) int {
+		grow(p + 1)
+		q := p
+		for da.check[q] >= 0 {
+			q = int(skip[q])
+			grow(q + 1)
+		}
+		for p != q {
+			p, skip[p] = int(skip[p]), int32(q)
+		}
+		return q
+	}
+
+	codes := make([]int32, 0, 16)
+	for s := 0; s < n; s++ {
+		lo, hi := childStart[s], childStart[s+1]
+		if lo == hi {
+			continue
+		}
+		codes = codes[:0]
+		first := int32(-1)
+		for t := lo; t < hi; t++ {
+			c := labels[t]
+			codes = append(codes, c)
+			if first < 0 || c < first {
+				first = c
+			}
+		}
+
+		// 依次尝试让编码最小的子节点落在各个空闲槽位，直到其他子节点也都能放下
+		var b int32
+		for p := firstFree(int(first)); ; p = firstFree(p + 1) {
+			b = int32(p) - first
+			ok := true
+			for _, c := range codes {
+				t := int(b + c)
+				grow(t + 1)
+				if da.check[t] >= 0 {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				break
+			}
+		}
+
+		da.base[s] = b
+		for i, c := range codes {
+			t := b + c
+			da.check[t] = int32(s)
+			da.next[t] = lo + int32(i)
+		}
+	}
+	return da
+}
+
+// child returns the child of state s on code c, or -1.
+func (da *doubleArray) child(s, c int32) int32 {
+	if t := da.base[s] + c; int(t) < len(da.check) && da.check[t] == s {
+		return da.next[t]
+	}
+	return -1
+}
@@ -0,0 +1,160 @@
+package flashtext
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// 四种实现都与暴力匹配的结果一致，包括子节点超过 8 个、需要二分查找的状态
+func TestBackends(t *testing.T) {
+	rnd := rand.New(rand.NewSource(4))
+	backends := []Backend{ArrayTrie, DoubleArray, DFA, RadixTrie}
+
+	// 26 个单字母关键词，根节点有 26 个子节点
+	letters := "abcdefghijklmnopqrstuvwxyz"
+	for _, backend := range backends {
+		kp := NewKeywordProcessor(WithBackend(backend))
+		kp.AddKeywordsFromList(strings.Split(letters, ""))
+		if n := len(kp.ExtractKeywords(letters)); n != 26 {
+			t.Errorf("%v: 期望 26 个匹配, 实际 %d 个", backend, n)
+		}
+		kp.Close()
+	}
+
+	for round := 0; round < 1200; round++ {
+		// 一半的轮次使用宽字符集，产生子节点很多的状态
+		alphabet := []rune("abcAB中文字😀")
+		if round%2 == 1 {
+			alphabet = []rune(letters + "ABCDEFG中文字😀")
+		}
+		randString := func(n int) string {
+			rs := make([]rune, n)
+			for i := range rs {
+				rs[i] = alphabet[rnd.Intn(len(alphabet))]
+			}
+			return string(rs)
+		}
+		var keywords []string
+		for i := 0; i < 1+rnd.Intn(60); i++ {
+			keywords = append(keywords, randString(1+rnd.Intn(4)))
+		}
+		text := randString(rnd.Intn(80))
+		kind := MatchKind(round % 3)
+		caseSensitive := round/3%2 == 0
+		opts := []Option{WithMatchKind(kind)}
+		if caseSensitive {
+			opts = append(opts, WithCaseSensitive())
+		}
+		want := bruteForce(keywords, text, caseSensitive, kind)
+
+		for _, backend := range backends {
+			kp := NewKeywordProcessor(append(opts, WithBackend(backend))...)
+			a := kp.AddKeywordsFromList(keywords).Build()
+			matches := a.ExtractKeywords(text)
+			if kind == MatchAll {
+				matches = sortedMatches(matches)
+			}
+			if got := matchStrings(matches); got != want {
+				t.Fatalf("%v 关键词 %v 文本 '%s' %v: 期望 '%s', 实际 '%s'", backend, keywords, text, kind, want, got)
+			}
+			for _, k := range keywords {
+				if !a.Contains(k) {
+					t.Fatalf("%v 应包含关键词 '%s'", backend, k)
+				}
+			}
+			if a.Contains(randString(5)) {
+				t.Fatalf("%v 不应包含 5 个字符的关键词", backend)
+			}
+			kp.Close()
+		}
+	}
+}
+
+// bruteForce returns the matches of keywords in text found by comparing every
+// keyword at every offset, formatted like matchStrings: all matches ordered by
+// start and end for MatchAll, the matches chosen by kind otherwise.
+func bruteForce(keywords []string, text string, caseSensitive bool, kind MatchKind) string {
+	fold := func(s string) []rune {
+		rs := []rune(s)
+		if !caseSensitive {
+			for i, r := range rs {
+				rs[i] = unicode.ToLower(r)
+			}
+		}
+		return rs
+	}
+	// 重复的关键词保留最先添加的
+	var unique [][]rune
+	seen := make(map[string]bool)
+	for _, k := range keywords {
+		if f := fold(k); !seen[string(f)] {
+			seen[string(f)] = true
+			unique = append(unique, f)
+		}
+	}
+
+	type match struct{ start, end, id, length int }
+	var offsets []int
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+	runes := fold(text)
+	var all []match
+	for i := range runes {
+		for id, k := range unique {
+			if i+len(k) <= len(runes) && string(runes[i:i+len(k)]) == string(k) {
+				all = append(all, match{offsets[i], offsets[i+len(k)], id, len(k)})
+			}
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		x, y := all[i], all[j]
+		if x.start != y.start {
+			return x.start < y.start
+		}
+		switch kind {
+		case LeftmostLongest:
+			return x.length > y.length
+		case LeftmostFirst:
+			return x.id < y.id
+		}
+		return x.end < y.end
+	})
+
+	var out []string
+	pos := 0
+	for _, m := range all {
+		if kind != MatchAll {
+			if m.start < pos {
+				continue
+			}
+			pos = m.end
+		}
+		out = append(out, fmt.Sprintf("%s[%d:%d]", text[m.start:m.end], m.start, m.end))
+	}
+	return strings.Join(out, " ")
+}
+
+// 构建后保留 Trie，修改时只改动关键词所在的路径
+func TestEditAfterBuild(t *testing.T) {
+	kp := NewKeywordProcessor(WithBackend(DoubleArray))
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"apple", "banana"})
+	banana := kp.root.find("banana", false)
+	kp.Build()
+	if !kp.RemoveKeyword("APPLE") || kp.RemoveKeyword("cherry") {
+		t.Error("删除结果不符合预期")
+	}
+	kp.AddKeyWord("cherry")
+	if s := matchStrings(kp.ExtractKeywords("apple banana cherry")); s != "banana[6:12] cherry[13:19]" {
+		t.Errorf("期望 'banana[6:12] cherry[13:19]', 实际 '%s'", s)
+	}
+	if kp.root.find("banana", false) != banana {
+		t.Error("修改后不应重建 Trie")
+	}
+}
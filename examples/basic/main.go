@@ -17,8 +17,8 @@ func main() {
 	kp.AddKeyWord("Python")
 	kp.AddKeywordsFromList([]string{"Java", "C++", "Rust"})
 
-	// 3. 构建索引 (必须调用!)
-	// Build the index (Required!)
+	// 3. 构建索引 (匹配时也会自动构建)
+	// Build the index (matching builds it automatically otherwise)
 	kp.Build()
 
 	// 4. 准备文本
@@ -0,0 +1,81 @@
+package flashtext
+
+import "sync/atomic"
+
+// HotProcessor serves matches from an Automaton that can be replaced at any
+// time, e.g. when a dictionary is edited from an admin console while
+// thousands of goroutines keep extracting keywords.
+//
+// Every call loads the current Automaton once with an atomic read, so readers
+// never block and never see a half-built trie. A replaced Automaton stays
+// valid for the scans still using it and is released by the garbage collector
+// once they finish.
+type HotProcessor struct {
+	current atomic.Pointer[Automaton]
+}
+
+// NewHotProcessor creates a HotProcessor serving a.
+func NewHotProcessor(a *Automaton) *HotProcessor {
+	hp := &HotProcessor{}
+	hp.current.Store(a)
+	return hp
+}
+
+// Automaton returns the Automaton currently served.
+func (hp *HotProcessor) Automaton() *Automaton {
+	return hp.current.Load()
+}
+
+// Swap atomically replaces the served Automaton with a and returns the old one.
+func (hp *HotProcessor) Swap(a *Automaton) *Automaton {
+	return hp.current.Swap(a)
+}
+
+// Reload builds kp and serves the resulting Automaton.
+// The build happens before the swap, so readers keep using the old Automaton
+// until the new one is complete.
+func (hp *HotProcessor) Reload(kp *KeywordProcessor) {
+	hp.current.Store(kp.Build())
+}
+
+// ExtractKeywords searches for keywords in a string with the current Automaton.
+func (hp *HotProcessor) ExtractKeywords(sentence string) []Match {
+	return hp.current.Load().ExtractKeywords(sentence)
+}
+
+// ExtractKeywordsFromBytes searches for keywords in a byte slice with the current Automaton.
+func (hp *HotProcessor) ExtractKeywordsFromBytes(sentence []byte) []Match {
+	return hp.current.Load().ExtractKeywordsFromBytes(sentence)
+}
+
+// AppendMatches appends the matches in text to dst using the current Automaton.
+func (hp *HotProcessor) AppendMatches(dst []Match, text string) []Match {
+	return hp.current.Load().AppendMatches(dst, text)
+}
+
+// FindAll calls fn for each match in text using the current Automaton.
+func (hp *HotProcessor) FindAll(text string, fn func(Match) bool) error {
+	return hp.current.Load().FindAll(text, fn)
+}
+
+// FindAllBytes calls fn for each match in text using the current Automaton.
+func (hp *HotProcessor) FindAllBytes(text []byte, fn func(Match) bool) error {
+	return hp.current.Load().FindAllBytes(text, fn)
+}
+
+// ReplaceKeywords replaces every keyword found in text with its clean name
+// using the current Automaton.
+func (hp *HotProcessor) ReplaceKeywords(text string) string {
+	return hp.current.Load().ReplaceKeywords(text)
+}
+
+// ReplaceKeywordsFunc replaces every keyword found in text with the string
+// returned by repl using the current Automaton.
+func (hp *HotProcessor) ReplaceKeywordsFunc(text string, repl func(m Match) string) string {
+	return hp.current.Load().ReplaceKeywordsFunc(text, repl)
+}
+
+// Redact masks every keyword found in text using the current Automaton.
+func (hp *HotProcessor) Redact(text string, opts RedactOptions) string {
+	return hp.current.Load().Redact(text, opts)
+}
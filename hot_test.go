@@ -0,0 +1,99 @@
+package flashtext
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// 自动机构建后不再变化
+func TestAutomatonImmutable(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	a := kp.AddKeywordsFromList([]string{"he", "she"}).Build()
+	if kp.Build() != a {
+		t.Error("未修改关键词时 Build 应返回同一个自动机")
+	}
+
+	kp.AddKeyWord("hers")
+	kp.RemoveKeyword("she")
+	b := kp.Build()
+	if b == a {
+		t.Fatal("修改关键词后 Build 应返回新的自动机")
+	}
+	if s := matchStrings(sortedMatches(a.ExtractKeywords("ushers"))); s != "she[1:4] he[2:4]" {
+		t.Errorf("旧自动机: 期望 'she[1:4] he[2:4]', 实际 '%s'", s)
+	}
+	if s := matchStrings(sortedMatches(b.ExtractKeywords("ushers"))); s != "he[2:4] hers[2:6]" {
+		t.Errorf("新自动机: 期望 'he[2:4] hers[2:6]', 实际 '%s'", s)
+	}
+}
+
+// 热更新时读取不阻塞且总能看到完整的自动机
+func TestHotProcessorReload(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	hp := NewHotProcessor(kp.AddKeyWord("apple").Build())
+
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				// 任何时刻都至少能匹配 "apple"
+				if n := len(hp.ExtractKeywords("apple banana")); n < 1 || n > 2 {
+					t.Errorf("期望 1 或 2 个匹配, 实际 %d 个", n)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			kp.AddKeyWord("banana")
+		} else {
+			kp.RemoveKeyword("banana")
+		}
+		hp.Reload(kp)
+	}
+	stop.Store(true)
+	wg.Wait()
+
+	kp2 := NewKeywordProcessor()
+	defer kp2.Close()
+	old := hp.Swap(kp2.AddKeyWord("banana").Build())
+	if len(old.ExtractKeywords("apple")) != 1 {
+		t.Error("被替换的自动机应仍可使用")
+	}
+	if s := matchStrings(hp.ExtractKeywords("apple banana")); s != "banana[6:12]" {
+		t.Errorf("期望 'banana[6:12]', 实际 '%s'", s)
+	}
+}
+
+// 处理器本身也可以在匹配的同时增删关键词
+func TestProcessorConcurrentEdit(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeyWord("apple")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if len(kp.ExtractKeywords("apple banana")) < 1 {
+					t.Error("期望至少匹配 'apple'")
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 200; i++ {
+		kp.AddKeyWord("banana")
+		kp.RemoveKeyword("banana")
+	}
+	wg.Wait()
+}
@@ -0,0 +1,67 @@
+package flashtext
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// InvalidUTF8Mode selects how bytes that are not valid UTF-8 are matched.
+// Whatever the mode, offsets are byte offsets in the raw input.
+type InvalidUTF8Mode int
+
+const (
+	// InvalidUTF8AsByte reads each invalid byte as a character of its own. It
+	// matches the same invalid byte in a keyword and nothing else, so it ends
+	// any match in progress.
+	InvalidUTF8AsByte InvalidUTF8Mode = iota
+	// InvalidUTF8Skip ignores invalid bytes: a keyword still matches when
+	// invalid bytes are interleaved with its characters, and the match spans them.
+	InvalidUTF8Skip
+	// InvalidUTF8Fail stops at the first invalid byte. The methods returning
+	// an error, such as FindAll, Stream and ExtractFromReader, report the
+	// matches before it and return an *InvalidUTF8Error. The methods without
+	// an error result, such as ExtractKeywords, AppendMatches and Redact,
+	// cannot report it and read invalid bytes as in InvalidUTF8AsByte.
+	InvalidUTF8Fail
+)
+
+func (m InvalidUTF8Mode) String() string {
+	switch m {
+	case InvalidUTF8AsByte:
+		return "InvalidUTF8AsByte"
+	case InvalidUTF8Skip:
+		return "InvalidUTF8Skip"
+	case InvalidUTF8Fail:
+		return "InvalidUTF8Fail"
+	default:
+		return "InvalidUTF8Mode(?)"
+	}
+}
+
+// InvalidUTF8Error reports invalid UTF-8 met in InvalidUTF8Fail mode.
+type InvalidUTF8Error struct {
+	Offset int // 无效字节在输入中的字节偏移
+}
+
+func (e *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("flashtext: invalid UTF-8 at byte offset %d", e.Offset)
+}
+
+// byteRune is the character an invalid byte b is read as. It lies above
+// utf8.MaxRune, so no valid rune equals it.
+func byteRune(b byte) rune {
+	return utf8.MaxRune + 1 + rune(b)
+}
+
+// decodeRune decodes the first character of s like utf8.DecodeRuneInString,
+// except that an invalid byte is read as its byteRune.
+func decodeRune(s string) (rune, int) {
+	if c := s[0]; c < utf8.RuneSelf {
+		return rune(c), 1
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError && size == 1 {
+		return byteRune(s[0]), 1
+	}
+	return r, size
+}
@@ -0,0 +1,200 @@
+package flashtext
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// 无效 UTF-8 的三种处理方式，位置均为原始字节偏移
+func TestInvalidUTF8(t *testing.T) {
+	keywords := []string{"abcd", "cd", "b\xffc", "�", "中文"}
+	text := "ab\xffcd \xfe中文"
+
+	tests := []struct {
+		name     string
+		mode     InvalidUTF8Mode
+		expected string
+		found    string // FindAll 的结果，报错模式下在无效字节处停止
+	}{
+		{"按字节", InvalidUTF8AsByte, "b\xffc[1:4] cd[3:5] 中文[7:13]", "b\xffc[1:4] cd[3:5] 中文[7:13]"},
+		{"跳过", InvalidUTF8Skip, "ab\xffcd[0:5] cd[3:5] 中文[7:13]", "ab\xffcd[0:5] cd[3:5] 中文[7:13]"},
+		{"报错", InvalidUTF8Fail, "b\xffc[1:4] cd[3:5] 中文[7:13]", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kp := NewKeywordProcessor(WithInvalidUTF8(tt.mode))
+			defer kp.Close()
+			kp.AddKeywordsFromList(keywords)
+
+			if got := matchStrings(kp.ExtractKeywordsFromBytes([]byte(text))); got != tt.expected {
+				t.Errorf("期望 '%s', 实际 '%s'", tt.expected, got)
+			}
+			var got []Match
+			err := kp.FindAll(text, func(m Match) bool {
+				got = append(got, m)
+				return true
+			})
+			if s := matchStrings(got); s != tt.found {
+				t.Errorf("FindAll 期望 '%s', 实际 '%s'", tt.found, s)
+			}
+			if (err != nil) != (tt.mode == InvalidUTF8Fail) {
+				t.Errorf("错误不符合预期: %v", err)
+			}
+		})
+	}
+}
+
+// 报错模式返回无效字节的位置，并保留之前的匹配
+func TestInvalidUTF8Fail(t *testing.T) {
+	kp := NewKeywordProcessor(WithInvalidUTF8(InvalidUTF8Fail), WithMatchKind(LeftmostLongest))
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"ab", "abc", "中"})
+
+	text := "ab 中ab\xe4\xb8"
+	var got []Match
+	err := kp.FindAll(text, func(m Match) bool {
+		got = append(got, m)
+		return true
+	})
+	var invalid *InvalidUTF8Error
+	if !errors.As(err, &invalid) || invalid.Offset != 8 {
+		t.Fatalf("期望在偏移 8 报错, 实际 %v", err)
+	}
+	if s := matchStrings(got); s != "ab[0:2] 中[3:6] ab[6:8]" {
+		t.Errorf("期望保留之前的匹配, 实际 '%s'", s)
+	}
+
+	// 流式输入中被截断的字符在 Flush 时才能确定无效
+	st := kp.NewStream(nil)
+	if _, err := st.Write([]byte(text)); err != nil {
+		t.Errorf("不完整的字符不应立即报错: %v", err)
+	}
+	if err := st.Flush(); !errors.As(err, &invalid) || invalid.Offset != 8 {
+		t.Errorf("期望 Flush 在偏移 8 报错, 实际 %v", err)
+	}
+	if err := kp.ExtractFromReader(strings.NewReader("x\xff"), func(Match) bool { return true }); err == nil {
+		t.Error("期望 ExtractFromReader 返回错误")
+	}
+
+	// 没有错误返回值的方法无法报错，把无效字节当作独立字符继续匹配
+	text += "abc"
+	want := "ab[0:2] 中[3:6] ab[6:8] abc[10:13]"
+	for name, got := range map[string][]Match{
+		"ExtractKeywords":          kp.ExtractKeywords(text),
+		"ExtractKeywordsFromBytes": kp.ExtractKeywordsFromBytes([]byte(text)),
+		"AppendMatches":            kp.AppendMatches(nil, text),
+	} {
+		if s := matchStrings(got); s != want {
+			t.Errorf("%s: 期望 '%s', 实际 '%s'", name, want, s)
+		}
+	}
+	if r := kp.Build().Redact(text, RedactOptions{}); !strings.Contains(r, "\xe4\xb8") || strings.Contains(r, "abc") {
+		t.Errorf("Redact 应保留无效字节并遮蔽之后的匹配, 实际 '%s'", r)
+	}
+}
+
+// 跳过模式下分片输入与整体提取一致
+func TestInvalidUTF8SkipStream(t *testing.T) {
+	for _, kind := range []MatchKind{MatchAll, LeftmostLongest} {
+		kp := NewKeywordProcessor(WithInvalidUTF8(InvalidUTF8Skip), WithMatchKind(kind))
+		kp.AddKeywordsFromList([]string{"hers", "he", "she", "毛泽东", "泽东"})
+		text := strings.Repeat("h\xffers\xfe\xfd 毛\xff泽东 s\xe4he ", 20)
+		want := matchStrings(kp.ExtractKeywords(text))
+
+		var got []Match
+		st := kp.NewStream(func(m Match) bool {
+			got = append(got, m)
+			return true
+		})
+		data := []byte(text)
+		for i := 0; i < len(data); i += 3 {
+			end := i + 3
+			if end > len(data) {
+				end = len(data)
+			}
+			st.Write(data[i:end])
+		}
+		st.Flush()
+		if s := matchStrings(got); s != want {
+			t.Errorf("%v: 期望 '%s', 实际 '%s'", kind, want, s)
+		}
+		kp.Close()
+	}
+}
+
+// 字节输入原地扫描，返回的匹配不与输入共享内存
+func TestExtractFromBytesDetached(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"apple", "pie"})
+
+	data := []byte("an apple pie")
+	matches := kp.ExtractKeywordsFromBytes(data)
+	copy(data, "xxxxxxxxxxxx")
+	if s := matchStrings(matches); s != "apple[3:8] pie[9:12]" {
+		t.Errorf("匹配文本被输入修改影响: '%s'", s)
+	}
+
+	// 只复制匹配本身，不复制匹配之间的文本
+	kp = NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"apple", "pie"}).Build()
+	data = bytes.Repeat([]byte("x"), 1<<20)
+	copy(data, "apple")
+	copy(data[len(data)-3:], "pie")
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	matches = kp.ExtractKeywordsFromBytes(data)
+	runtime.ReadMemStats(&after)
+	// 除结果切片外只分配匹配文本的副本
+	n := after.TotalAlloc - before.TotalAlloc - uint64(cap(matches))*uint64(unsafe.Sizeof(Match{}))
+	if n > 1<<12 {
+		t.Errorf("期望只复制匹配的文本, 实际另外分配 %d 字节", n)
+	}
+	if s := matchStrings(matches); s != "apple[0:5] pie[1048573:1048576]" {
+		t.Errorf("期望 'apple[0:5] pie[1048573:1048576]', 实际 '%s'", s)
+	}
+}
+
+// 重叠的匹配合并为一段复制，分配不随匹配长度之和增长
+func TestExtractFromBytesOverlapping(t *testing.T) {
+	var keywords []string
+	for i := 1; i <= 200; i++ {
+		keywords = append(keywords, strings.Repeat("a", i))
+	}
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	a := kp.AddKeywordsFromList(keywords).Build()
+
+	data := append(bytes.Repeat([]byte("a"), 2000), " b "...)
+	data = append(data, bytes.Repeat([]byte("a"), 100)...)
+	text := string(data)
+	matches := a.extractKeywords(bytesToString(data), MatchAll)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	detach(len(matches), func(i int) *Match { return &matches[i] }, data)
+	runtime.ReadMemStats(&after)
+	// 逐个复制时超过 100MB，合并后不超过输入长度 (分配大小按等级取整)
+	if n := after.TotalAlloc - before.TotalAlloc; n > 2*uint64(len(data)) {
+		t.Errorf("期望最多复制 %d 字节, 实际分配 %d 字节", len(data), n)
+	}
+	copy(data, bytes.Repeat([]byte("x"), len(data)))
+	for _, m := range matches {
+		if m.MatchString() != text[m.start:m.end] {
+			t.Fatalf("匹配 [%d:%d] 的文本错误: '%s'", m.start, m.end, m.MatchString())
+		}
+	}
+
+	// 其他顺序在复制之后排序
+	kp = NewKeywordProcessor(WithMatchOrder(OrderByStart))
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"ab", "abc", "bc", "d"})
+	want := "abc[0:3] ab[0:2] bc[1:3] d[4:5]"
+	if s := matchStrings(kp.ExtractKeywordsFromBytes([]byte("abc d"))); s != want {
+		t.Errorf("期望 '%s', 实际 '%s'", want, s)
+	}
+}
@@ -1,10 +1,9 @@
 package flashtext
 
 import (
-	"context"
-	"math"
+	"sync"
+	"sync/atomic"
 	"unicode"
-	"unicode/utf8"
 )
 
 // Package flashtext implements the Aho-Corasick algorithm for efficient keyword matching.
@@ -16,13 +15,31 @@ import (
 type WalkFn func(start, end int) bool
 
 // KeywordProcessor controls the keyword matching process.
-// It holds the AC automaton trie and configuration.
+// It holds the editable keyword trie and configuration, and compiles them
+// into an immutable Automaton on Build.
+//
+// A processor is either built, when its Automaton agrees with the keywords,
+// or dirty after keywords were added or removed. Matching on a dirty processor
+// builds it first, so calling Build is only needed to pay that cost up front.
+// Adding or removing a keyword only touches its path in the trie, but the
+// Automaton is compiled as a whole: the next Build after an edit costs as
+// much as building the dictionary from scratch. Batch the edits to a large
+// dictionary and build it off the hot path, e.g. with HotProcessor.Reload.
+// All methods are safe for concurrent use.
 type KeywordProcessor struct {
-	cancel        context.CancelFunc
-	root          *Node
-	stats         *stats // 异步统计模块，根据词库动态调整 density ，跑的越久性能越好
-	caseSensitive bool   // 匹配是否区分大小写
-	matchDensity  float64
+	root          *Node                     // 可修改的 Trie，构建后仍然保留，增删关键词只修改一条路径
+	entries       []entry                   // 所有关键词，Node.exist 中保存的是这里的下标
+	stats         *stats                    // 统计模块，根据匹配结果动态调整 density ，跑的越久性能越好
+	caseSensitive bool                      // 匹配是否区分大小写
+	mu            sync.Mutex                // 保护 Trie 的修改和构建
+	compiled      atomic.Pointer[Automaton] // 最近一次构建的自动机，为 nil 表示需要重新构建
+	size          int                       // 关键词数量
+	boundaries    *boundaries               // 非 nil 时只匹配完整单词
+	matchKind     MatchKind                 // 重叠匹配的取舍策略
+	matchOrder    MatchOrder                // 返回切片时匹配的排序方式
+	invalidUTF8   InvalidUTF8Mode           // 文本中无效 UTF-8 字节的处理方式
+	backend       Backend                   // 构建后自动机转移的存储方式
+	dfaLimit      int                       // DFA 转移表的内存预算，字节
 }
 type Option func(*KeywordProcessor)
 
@@ -33,14 +50,73 @@ func WithCaseSensitive() Option {
 	}
 }
 
+// 只匹配完整单词：匹配的前一个字符和后一个字符都必须是单词边界。
+// 默认字母、数字和下划线组成单词；中文、日文、泰文等不使用空格分词的文字每个字符自成一词。
+func WithWordBoundaries() Option {
+	return func(processor *KeywordProcessor) {
+		if processor.boundaries == nil {
+			processor.boundaries = &boundaries{}
+		}
+	}
+}
+
+// 自定义组成单词的字符集合 (同 Python FlashText 的 non_word_boundaries)，并开启完整单词匹配。
+// 不在 chars 中的字符都视为单词边界。
+func WithNonWordBoundaries(chars string) Option {
+	return func(processor *KeywordProcessor) {
+		set := make(map[rune]struct{}, len(chars))
+		for _, r := range chars {
+			set[r] = struct{}{}
+		}
+		processor.boundaries = &boundaries{wordRunes: set}
+	}
+}
+
+// 重叠匹配的取舍策略，默认 MatchAll 返回所有重叠匹配
+func WithMatchKind(kind MatchKind) Option {
+	return func(processor *KeywordProcessor) {
+		processor.matchKind = kind
+	}
+}
+
+// 返回切片的方法中匹配的排序方式，默认 OrderByEnd 按结束位置排序，结束位置相同时长的在前。
+// FindAll 和 Stream 总是按默认顺序逐个报告。
+func WithMatchOrder(order MatchOrder) Option {
+	return func(processor *KeywordProcessor) {
+		processor.matchOrder = order
+	}
+}
+
+// 文本中无效 UTF-8 字节的处理方式，默认 InvalidUTF8AsByte 将每个无效字节视为一个独立字符。
+// 匹配位置始终是原始输入中的字节偏移。
+func WithInvalidUTF8(mode InvalidUTF8Mode) Option {
+	return func(processor *KeywordProcessor) {
+		processor.invalidUTF8 = mode
+	}
+}
+
+// 构建后自动机转移的存储方式，默认 ArrayTrie。对延迟敏感的中小词库可以选择 DFA，以长关键词为主的词库可以选择 RadixTrie。
+func WithBackend(backend Backend) Option {
+	return func(processor *KeywordProcessor) {
+		processor.backend = backend
+	}
+}
+
+// DFA 转移表的内存预算 (字节)，默认 DefaultDFAMemoryLimit。超出预算时退回 ArrayTrie。
+func WithDFAMemoryLimit(bytes int) Option {
+	return func(processor *KeywordProcessor) {
+		processor.dfaLimit = bytes
+	}
+}
+
 // NewKeywordProcessor creates a new processor instance.
 // caseSensitive: if true, matches are case-sensitive.
 func NewKeywordProcessor(opts ...Option) *KeywordProcessor {
-	ctx := context.Background()
 	processor := &KeywordProcessor{
 		root:          newNode(),
 		caseSensitive: false,
-		stats:         newStats(ctx, defaultAlpha, defaultBuffer),
+		dfaLimit:      DefaultDFAMemoryLimit,
+		stats:         newStats(defaultAlpha),
 	}
 	for _, opt := range opts {
 		opt(processor)
@@ -48,141 +124,315 @@ func NewKeywordProcessor(opts ...Option) *KeywordProcessor {
 	return processor
 }
 
-func (kp *KeywordProcessor) setItem(keyword string) {
+// setItem inserts keyword into the trie and returns its entry id,
+// or -1 if the keyword is empty. kp.mu must be held.
+func (kp *KeywordProcessor) setItem(keyword, cleanName string, payload any) int {
 	if len(keyword) == 0 {
-		return
+		return -1
 	}
+	if len(cleanName) == 0 {
+		cleanName = keyword
+	}
+	kp.compiled.Store(nil)
+
+	node := kp.insert(keyword)
+	// 重复添加同一个关键词时只更新其标准名称和附加数据
+	if id := node.keywordAt(kp.entries); id >= 0 {
+		kp.entries[id].cleanName = cleanName
+		kp.entries[id].payload = payload
+		return id
+	}
+	// 记录当前匹配词的 id
+	kp.size++
+	id := len(kp.entries)
+	node.exist = append(node.exist, id)
+	kp.entries = append(kp.entries, entry{keyword: keyword, cleanName: cleanName, length: node.depth, payload: payload})
+	return id
+}
 
+// insert returns the node spelling keyword, creating the missing nodes.
+func (kp *KeywordProcessor) insert(keyword string) *Node {
 	node := kp.root
-	for _, char := range keyword {
+	for i := 0; i < len(keyword); {
+		char, size := decodeRune(keyword[i:])
+		i += size
 		if !kp.caseSensitive {
 			char = unicode.ToLower(char)
 		}
 		if _, ok := node.children[char]; !ok {
-			node.children[char] = newNode()
+			child := newNode()
+			child.depth = node.depth + 1
+			node.children[char] = child
 		}
 		node = node.children[char]
 	}
-	// 记录当前匹配词的长度
-	node.exist = append(node.exist, len([]rune(keyword)))
-}
-
-// Build constructs the failure pointers for the AC automaton.
-// This MUST be called after all keywords are added and before matching.
-func (kp *KeywordProcessor) Build() {
-	// 优化: 预分配队列容量
-	queue := make([]*Node, 0, 128)
-	queue = append(queue, kp.root)
-
-	for len(queue) > 0 {
-		currentNode := queue[0]
-		queue = queue[1:]
-
-		for char, childNode := range currentNode.children {
-			queue = append(queue, childNode)
-			faFail := currentNode.failure
-
-			for faFail != nil && faFail.children[char] == nil {
-				faFail = faFail.failure
-			}
-			childNode.failure = kp.root
-			if faFail != nil {
-				childNode.failure = faFail.children[char]
-			}
-			// Merge exist and deduplicate
-			childNode.exist = append(childNode.exist, childNode.failure.exist...)
-			tmp := make(map[int]struct{}, len(childNode.exist))
-			for _, l := range childNode.exist {
-				tmp[l] = struct{}{}
-			}
-			if len(tmp) < len(childNode.exist) {
-				childNode.exist = childNode.exist[:0]
-				for l := range tmp {
-					childNode.exist = append(childNode.exist, l)
-				}
-			}
+	return node
+}
+
+// lookup returns the entry id of keyword, or -1 if it was not added.
+// kp.mu must be held.
+func (kp *KeywordProcessor) lookup(keyword string) int {
+	if len(keyword) == 0 {
+		return -1
+	}
+	node := kp.root.find(keyword, kp.caseSensitive)
+	if node == nil {
+		return -1
+	}
+	return node.keywordAt(kp.entries)
+}
+
+// Build compiles the keywords into an immutable Automaton and returns it.
+// Matching builds a dirty processor automatically; call Build after adding
+// keywords to avoid paying for it on the first match.
+// Calling it again without modifications returns the same Automaton.
+func (kp *KeywordProcessor) Build() *Automaton {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	return kp.build()
+}
+
+// automaton returns the current Automaton, building it if keywords changed.
+func (kp *KeywordProcessor) automaton() *Automaton {
+	if a := kp.compiled.Load(); a != nil {
+		return a
+	}
+	return kp.Build()
+}
+
+// build constructs the failure pointers and hands the trie to a new Automaton.
+// kp.mu must be held.
+func (kp *KeywordProcessor) build() *Automaton {
+	if a := kp.compiled.Load(); a != nil {
+		return a
+	}
+	if len(kp.entries) > kp.size {
+		kp.compact()
+	}
+	a := &Automaton{
+		entries:       append([]entry(nil), kp.entries...),
+		size:          kp.size,
+		maxLen:        maxLen(kp.entries),
+		stats:         kp.stats,
+		caseSensitive: kp.caseSensitive,
+		boundaries:    kp.boundaries,
+		matchKind:     kp.matchKind,
+		matchOrder:    kp.matchOrder,
+		invalidUTF8:   kp.invalidUTF8,
+		backend:       kp.backend,
+	}
+	a.compile(kp.root, kp.dfaLimit)
+	kp.compiled.Store(a)
+	return a
+}
+
+// compact drops the slots of removed keywords from entries and renumbers the
+// others, keeping the order they were added in, so that adding and removing
+// keywords does not grow entries. kp.mu must be held.
+func (kp *KeywordProcessor) compact() {
+	ids := make([]int, len(kp.entries))
+	live := kp.entries[:0]
+	for id, e := range kp.entries {
+		ids[id] = len(live)
+		if e.length > 0 {
+			live = append(live, e)
+		}
+	}
+	// 清空尾部，释放已删除关键词的附加数据
+	for i := len(live); i < len(kp.entries); i++ {
+		kp.entries[i] = entry{}
+	}
+	kp.entries = live
+	for stack := []*Node{kp.root}; len(stack) > 0; {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for i, id := range node.exist {
+			node.exist[i] = ids[id]
+		}
+		for _, child := range node.children {
+			stack = append(stack, child)
+		}
+	}
+}
+
+// maxLen returns the rune length of the longest keyword.
+func maxLen(entries []entry) int {
+	n := 0
+	for _, e := range entries {
+		if e.length > n {
+			n = e.length
 		}
 	}
+	return n
 }
 
 // AddKeyWord adds a single keyword to the processor.
 // Returns the processor for chaining.
 func (kp *KeywordProcessor) AddKeyWord(keyword string) *KeywordProcessor {
-	kp.setItem(keyword)
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	kp.setItem(keyword, keyword, nil)
+	return kp
+}
+
+// AddKeywordWithCleanName adds a keyword whose matches report cleanName
+// through Match.CleanName, e.g. "NYC" and "New York City" -> "New York".
+// An empty cleanName falls back to the keyword itself.
+// Adding the same keyword again replaces its clean name.
+// Returns the processor for chaining.
+func (kp *KeywordProcessor) AddKeywordWithCleanName(keyword, cleanName string) *KeywordProcessor {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	kp.setItem(keyword, cleanName, nil)
 	return kp
 }
 
 // AddKeywordsFromList adds multiple keywords from a slice.
 // Returns the processor for chaining.
 func (kp *KeywordProcessor) AddKeywordsFromList(keywords []string) *KeywordProcessor {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
 	for _, keyword := range keywords {
-		kp.setItem(keyword)
+		kp.setItem(keyword, keyword, nil)
 	}
 	return kp
 }
 
-// walk
-func (kp *KeywordProcessor) walk(sentence []rune, wf WalkFn) {
-	node := kp.root
+// RemoveKeyword removes a keyword from the processor and prunes the trie
+// nodes only it used. It reports whether the keyword was present.
+// The next Build or match recompiles the automaton from the remaining
+// keywords, which costs as much as a Build from scratch.
+func (kp *KeywordProcessor) RemoveKeyword(keyword string) bool {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	return kp.deleteItem(keyword) >= 0
+}
 
-	for i, r := range sentence {
-		if !kp.caseSensitive {
-			r = unicode.ToLower(r)
-		}
-		for node.children[r] == nil && node != kp.root {
-			node = node.failure
+// RemoveKeywordsFromList removes multiple keywords from a slice.
+// It returns the number of keywords that were present.
+func (kp *KeywordProcessor) RemoveKeywordsFromList(keywords []string) int {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	removed := 0
+	for _, keyword := range keywords {
+		if kp.deleteItem(keyword) >= 0 {
+			removed++
 		}
+	}
+	return removed
+}
+
+// deleteItem removes keyword from the trie and returns its former entry id,
+// or -1 if the keyword is not present. kp.mu must be held.
+func (kp *KeywordProcessor) deleteItem(keyword string) int {
+	if len(keyword) == 0 {
+		return -1
+	}
+	if kp.lookup(keyword) < 0 {
+		return -1
+	}
+	kp.compiled.Store(nil)
 
-		if node.children[r] != nil {
-			node = node.children[r]
+	path := make([]*Node, 0, len(keyword)+1)
+	chars := make([]rune, 0, len(keyword))
+	node := kp.root
+	path = append(path, node)
+	for i := 0; i < len(keyword); {
+		char, size := decodeRune(keyword[i:])
+		i += size
+		if !kp.caseSensitive {
+			char = unicode.ToLower(char)
 		}
+		node = node.children[char]
+		path = append(path, node)
+		chars = append(chars, char)
+	}
+
+	id := node.keywordAt(kp.entries)
+	node.exist = nil
+	// 已删除的关键词先保留空位，保证其他关键词的 id 不变，空位较多或构建时再压缩
+	kp.entries[id] = entry{}
+	kp.size--
 
-		for _, l := range node.exist {
-			if !wf(i+1-l, i+1) {
-				return
-			}
+	// 自底向上剪掉不再被任何关键词使用的节点
+	for i := len(path) - 1; i > 0; i-- {
+		n := path[i]
+		if len(n.children) > 0 || n.keywordAt(kp.entries) >= 0 {
+			break
 		}
+		delete(path[i-1].children, chars[i-1])
+	}
+	if len(kp.entries) > 2*kp.size+64 {
+		kp.compact()
+	}
+	return id
+}
+
+// Len returns the number of keywords in the processor.
+func (kp *KeywordProcessor) Len() int {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	return kp.size
+}
+
+// Contains reports whether keyword was added to the processor,
+// ignoring case unless the processor is case-sensitive.
+func (kp *KeywordProcessor) Contains(keyword string) bool {
+	_, ok := kp.GetKeyword(keyword)
+	return ok
+}
+
+// GetKeyword returns the clean name of keyword and whether it was added,
+// ignoring case unless the processor is case-sensitive.
+func (kp *KeywordProcessor) GetKeyword(keyword string) (cleanName string, ok bool) {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	id := kp.lookup(keyword)
+	if id < 0 {
+		return "", false
 	}
+	return kp.entries[id].cleanName, true
+}
+
+// Keywords returns an iterator over the keywords and their clean names in
+// lexicographic order of the trie. It iterates over the current Automaton,
+// building it if keywords changed, so edits during iteration are not seen.
+func (kp *KeywordProcessor) Keywords() func(yield func(keyword, cleanName string) bool) {
+	return kp.automaton().Keywords()
 }
 
 // ExtractKeywords searches for keywords in a string.
-// It returns a slice of all matches found.
+// It returns a slice of the matches selected by the processor's MatchKind,
+// all overlapping matches by default.
 func (kp *KeywordProcessor) ExtractKeywords(sentence string) []Match {
-	// 优化: 预分配容量
-	runes := []rune(sentence)
-	if len(runes) == 0 {
-		return nil
-	}
-	density := kp.stats.getDensity()
-	capEstimate := int(math.Ceil(float64(len(runes)) * density))
-	matches := make([]Match, 0, capEstimate)
-	byteOffsets := make([]int, len(runes)+1)
-	for i, r := range runes {
-		byteOffsets[i+1] = byteOffsets[i] + utf8.RuneLen(r)
-	}
-	kp.walk(runes, func(start, end int) bool {
-		startByte := byteOffsets[start]
-		endByte := byteOffsets[end]
-		matches = append(matches, Match{
-			start: startByte,
-			end:   endByte,
-			match: sentence[startByte:endByte],
-		})
-		return true
-	})
-	kp.stats.add(len(matches), len(runes))
-	return matches
+	return kp.automaton().ExtractKeywords(sentence)
 }
 
 // ExtractKeywordsFromBytes searches for keywords in a byte slice.
-// It returns a slice of all matches found.
+// It returns a slice of the matches selected by the processor's MatchKind.
 func (kp *KeywordProcessor) ExtractKeywordsFromBytes(sentence []byte) []Match {
-	// 优化: 预分配容量 + 统一使用 walk
-	return kp.ExtractKeywords(string(sentence))
+	return kp.automaton().ExtractKeywordsFromBytes(sentence)
 }
 
-func (kp *KeywordProcessor) Close() {
-	if kp.stats != nil {
-		kp.stats.close()
-	}
+// AppendMatches appends the matches in text to dst and returns the extended slice.
+// See Automaton.AppendMatches.
+func (kp *KeywordProcessor) AppendMatches(dst []Match, text string) []Match {
+	return kp.automaton().AppendMatches(dst, text)
+}
+
+// FindAll calls fn for each match in text without allocating.
+// See Automaton.FindAll.
+func (kp *KeywordProcessor) FindAll(text string, fn func(Match) bool) error {
+	return kp.automaton().FindAll(text, fn)
 }
+
+// FindAllBytes calls fn for each match in text without copying it.
+// See Automaton.FindAllBytes.
+func (kp *KeywordProcessor) FindAllBytes(text []byte, fn func(Match) bool) error {
+	return kp.automaton().FindAllBytes(text, fn)
+}
+
+// Close does nothing and is kept for compatibility. A processor holds no
+// goroutine or other resource, so one that is dropped without Close is
+// simply collected by the GC.
+func (kp *KeywordProcessor) Close() {}
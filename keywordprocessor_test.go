@@ -2,6 +2,7 @@ package flashtext
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -167,3 +168,259 @@ func TestExtractFromBytes(t *testing.T) {
 		t.Errorf("期望3个匹配, 实际 %d 个", len(matches))
 	}
 }
+
+// 标准名称测试
+func TestCleanName(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordWithCleanName("NYC", "New York").
+		AddKeywordWithCleanName("New York City", "New York").
+		AddKeywordWithCleanName("big apple", "").
+		AddKeyWord("Python").
+		Build()
+
+	text := "I moved from nyc to New York City, the Big Apple, to write python."
+	expected := []string{"New York", "New York", "big apple", "Python"}
+	matches := kp.ExtractKeywords(text)
+	if len(matches) != len(expected) {
+		t.Fatalf("期望 %d 个匹配, 实际 %d 个", len(expected), len(matches))
+	}
+	for i, match := range matches {
+		if match.CleanName() != expected[i] {
+			t.Errorf("第 %d 个匹配: 期望标准名称 '%s', 实际 '%s'", i, expected[i], match.CleanName())
+		}
+	}
+}
+
+// 重复添加关键词时覆盖标准名称
+func TestCleanNameOverride(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordWithCleanName("java", "Java").
+		AddKeywordWithCleanName("JAVA", "Java SE").
+		Build()
+
+	matches := kp.ExtractKeywords("java")
+	if len(matches) != 1 {
+		t.Fatalf("期望 1 个匹配, 实际 %d 个", len(matches))
+	}
+	if matches[0].CleanName() != "Java SE" {
+		t.Errorf("期望标准名称 'Java SE', 实际 '%s'", matches[0].CleanName())
+	}
+}
+
+// 删除关键词测试
+func TestRemoveKeyword(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"he", "she", "hers"}).Build()
+
+	if kp.RemoveKeyword("her") {
+		t.Error("'her' 不是关键词, 不应删除成功")
+	}
+	if !kp.RemoveKeyword("HE") {
+		t.Fatal("删除 'HE' 失败")
+	}
+	if kp.RemoveKeyword("he") {
+		t.Error("'he' 已删除, 不应再次删除成功")
+	}
+
+	// "she" 节点合并过 "he"，删除后不能残留
+	matches := sortedMatches(kp.ExtractKeywords("hershey"))
+	if s := matchStrings(matches); s != "hers[0:4] she[3:6]" {
+		t.Errorf("期望 'hers[0:4] she[3:6]', 实际 '%s'", s)
+	}
+}
+
+// 删除关键词后剪枝，失败指针不能指向被剪掉的节点
+func TestRemoveKeywordPrune(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"abc", "xab", "bc"}).Build()
+
+	if n := kp.RemoveKeywordsFromList([]string{"abc", "bc", "nothing"}); n != 2 {
+		t.Fatalf("期望删除 2 个关键词, 实际 %d 个", n)
+	}
+	if _, ok := kp.root.children['a']; ok {
+		t.Error("'abc' 的节点应被剪掉")
+	}
+	if _, ok := kp.root.children['b']; ok {
+		t.Error("'bc' 的节点应被剪掉")
+	}
+	if s := matchStrings(kp.ExtractKeywords("xabc abc")); s != "xab[0:3]" {
+		t.Errorf("期望 'xab[0:3]', 实际 '%s'", s)
+	}
+
+	// 删除后重新添加
+	kp.AddKeyWord("bc").Build()
+	if s := matchStrings(kp.ExtractKeywords("xabc")); s != "xab[0:3] bc[2:4]" {
+		t.Errorf("期望 'xab[0:3] bc[2:4]', 实际 '%s'", s)
+	}
+}
+
+// 反复增删关键词时已删除的空位会被回收，添加顺序不变
+func TestRemoveKeywordCompact(t *testing.T) {
+	kp := NewKeywordProcessor(WithMatchOrder(OrderByInsertion))
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"c", "b", "a"})
+
+	for i := 0; i < 100; i++ {
+		kp.RemoveKeyword("b")
+		kp.AddKeyWord("b")
+		kp.ExtractKeywords("abc")
+	}
+	if len(kp.entries) != 3 {
+		t.Errorf("期望 3 个关键词槽位, 实际 %d 个", len(kp.entries))
+	}
+	if s := matchStrings(kp.ExtractKeywords("abc")); s != "c[2:3] a[0:1] b[1:2]" {
+		t.Errorf("期望 'c[2:3] a[0:1] b[1:2]', 实际 '%s'", s)
+	}
+
+	// 不构建时空位也不会无限增长
+	for i := 0; i < 1000; i++ {
+		kp.RemoveKeyword("b")
+		kp.AddKeyWord("b")
+	}
+	if len(kp.entries) > 2*kp.Len()+64 {
+		t.Errorf("空位未被回收: %d 个槽位, %d 个关键词", len(kp.entries), kp.Len())
+	}
+}
+
+// 忘记 Build 或 Build 之后继续添加关键词时自动构建
+func TestAutoBuild(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"he", "she"})
+
+	// 未调用 Build
+	if s := matchStrings(kp.ExtractKeywords("ushers")); s != "she[1:4] he[2:4]" {
+		t.Errorf("期望 'she[1:4] he[2:4]', 实际 '%s'", s)
+	}
+
+	// Build 之后继续添加
+	kp.AddKeyWord("hers")
+	if s := matchStrings(sortedMatches(kp.ExtractKeywords("ushers"))); s != "she[1:4] he[2:4] hers[2:6]" {
+		t.Errorf("期望 'she[1:4] he[2:4] hers[2:6]', 实际 '%s'", s)
+	}
+
+	// 删除后无需手动 Build
+	kp.RemoveKeyword("she")
+	if s := matchStrings(sortedMatches(kp.ExtractKeywords("ushers"))); s != "he[2:4] hers[2:6]" {
+		t.Errorf("期望 'he[2:4] hers[2:6]', 实际 '%s'", s)
+	}
+}
+
+// 词库查询测试
+func TestIntrospection(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"she", "he", "hers", "Python"}).
+		AddKeywordWithCleanName("NYC", "New York")
+
+	if kp.Len() != 5 {
+		t.Errorf("期望 5 个关键词, 实际 %d 个", kp.Len())
+	}
+	if !kp.Contains("HE") || !kp.Contains("python") {
+		t.Error("不区分大小写时应包含 'HE' 和 'python'")
+	}
+	if kp.Contains("her") || kp.Contains("") {
+		t.Error("不应包含 'her' 和空字符串")
+	}
+	if clean, ok := kp.GetKeyword("nyc"); !ok || clean != "New York" {
+		t.Errorf("期望 'New York', 实际 '%s' %v", clean, ok)
+	}
+
+	kp.RemoveKeyword("she")
+	kp.AddKeyWord("he") // 重复添加不增加数量
+	if kp.Len() != 4 {
+		t.Errorf("期望 4 个关键词, 实际 %d 个", kp.Len())
+	}
+
+	var got []string
+	for _, f := range []func(yield func(keyword, cleanName string) bool){kp.Keywords(), kp.Build().Keywords()} {
+		got = got[:0]
+		f(func(keyword, cleanName string) bool {
+			got = append(got, keyword+"="+cleanName)
+			return true
+		})
+		if s := strings.Join(got, " "); s != "he=he hers=hers NYC=New York Python=Python" {
+			t.Errorf("期望 'he=he hers=hers NYC=New York Python=Python', 实际 '%s'", s)
+		}
+	}
+
+	// 提前结束遍历
+	n := 0
+	kp.Keywords()(func(keyword, cleanName string) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("期望遍历 1 个关键词后停止, 实际 %d 个", n)
+	}
+}
+
+// 回调接口与 ExtractKeywords 结果一致且不分配内存
+func TestFindAll(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"he", "she", "hers", "毛泽东"}).Build()
+	text := "ushers 毛泽东 hershey"
+
+	var got []Match
+	kp.FindAll(text, func(m Match) bool {
+		got = append(got, m)
+		return true
+	})
+	if want := matchStrings(kp.ExtractKeywords(text)); matchStrings(got) != want {
+		t.Errorf("期望 '%s', 实际 '%s'", want, matchStrings(got))
+	}
+
+	got = got[:0]
+	kp.FindAllBytes([]byte(text), func(m Match) bool {
+		got = append(got, m)
+		return len(got) < 2
+	})
+	if s := matchStrings(got); s != "she[1:4] he[2:4]" {
+		t.Errorf("提前停止: 实际 '%s'", s)
+	}
+
+	count := 0
+	data := []byte(text)
+	allocs := testing.AllocsPerRun(100, func() {
+		kp.FindAll(text, func(m Match) bool {
+			count++
+			return true
+		})
+		kp.FindAllBytes(data, func(m Match) bool {
+			count++
+			return true
+		})
+	})
+	if allocs != 0 {
+		t.Errorf("期望不分配内存, 实际每次 %v 次", allocs)
+	}
+}
+
+// 追加到调用方提供的切片，复用时不分配内存
+func TestAppendMatches(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"he", "she", "hers"}).Build()
+
+	prefix := []Match{{match: "x", start: 0, end: 1}}
+	got := kp.AppendMatches(prefix, "hershey")
+	if s := matchStrings(got); s != "x[0:1] he[0:2] hers[0:4] she[3:6] he[4:6]" {
+		t.Errorf("追加结果错误: '%s'", s)
+	}
+
+	buf := make([]Match, 0, 8)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = kp.AppendMatches(buf[:0], "hershey")
+	})
+	if allocs != 0 {
+		t.Errorf("期望不分配内存, 实际每次 %v 次", allocs)
+	}
+	if len(buf) != 4 {
+		t.Errorf("期望 4 个匹配, 实际 %d", len(buf))
+	}
+}
@@ -0,0 +1,37 @@
+package flashtext
+
+// MatchKind selects which matches are reported when keywords overlap.
+type MatchKind int
+
+const (
+	// MatchAll reports every match, including overlapping ones.
+	MatchAll MatchKind = iota
+	// LeftmostLongest reports non-overlapping matches. Among the matches
+	// starting leftmost the longest one wins.
+	LeftmostLongest
+	// LeftmostFirst reports non-overlapping matches. Among the matches
+	// starting leftmost the keyword added first wins.
+	LeftmostFirst
+)
+
+func (k MatchKind) String() string {
+	switch k {
+	case MatchAll:
+		return "MatchAll"
+	case LeftmostLongest:
+		return "LeftmostLongest"
+	case LeftmostFirst:
+		return "LeftmostFirst"
+	default:
+		return "MatchKind(?)"
+	}
+}
+
+// prefer reports whether the keyword id should replace the pending keyword
+// when both match at the same start.
+func (a *Automaton) prefer(kind MatchKind, id, pending int) bool {
+	if kind == LeftmostFirst {
+		return id < pending
+	}
+	return a.entries[id].length > a.entries[pending].length
+}
@@ -0,0 +1,104 @@
+package flashtext
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func matchStrings(matches []Match) string {
+	var out []string
+	for _, m := range matches {
+		out = append(out, fmt.Sprintf("%s[%d:%d]", m.MatchString(), m.Start(), m.End()))
+	}
+	return strings.Join(out, " ")
+}
+
+// 非重叠匹配策略测试
+func TestMatchKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     MatchKind
+		keywords []string
+		text     string
+		expected string
+	}{
+		{"全部", MatchAll, []string{"sys", "system", "tem", "operating system"}, "operating system", "operating system[0:16] sys[10:13] system[10:16] tem[13:16]"},
+		{"最左最长", LeftmostLongest, []string{"sys", "system", "tem", "operating system"}, "the operating system", "operating system[4:20]"},
+		{"最左优先", LeftmostFirst, []string{"sys", "system", "tem", "operating system"}, "the system", "sys[4:7] tem[7:10]"},
+		{"最左最长重叠", LeftmostLongest, []string{"he", "she", "hers"}, "hershey", "hers[0:4] he[4:6]"},
+		{"最左优先回退", LeftmostFirst, []string{"ab", "abcd", "cd"}, "abcd", "ab[0:2] cd[2:4]"},
+		{"最左最长回退", LeftmostLongest, []string{"ab", "abcd", "bcx"}, "abcx", "ab[0:2]"},
+		{"中文", LeftmostLongest, []string{"AV", "AV演员", "AV演员色情"}, "AV演员色情表演", "AV演员色情[0:14]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kp := NewKeywordProcessor(WithMatchKind(tt.kind))
+			defer kp.Close()
+			kp.AddKeywordsFromList(tt.keywords).Build()
+			got := kp.ExtractKeywords(tt.text)
+			if tt.kind == MatchAll {
+				got = sortedMatches(got)
+			}
+			if s := matchStrings(got); s != tt.expected {
+				t.Errorf("期望 '%s', 实际 '%s'", tt.expected, s)
+			}
+		})
+	}
+}
+
+// sortedMatches orders matches by start then end so results can be compared.
+func sortedMatches(matches []Match) []Match {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].end < matches[j].end
+	})
+	return matches
+}
+
+// 与暴力实现对比最左最长结果
+func TestLeftmostLongestRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "abc"[rnd.Intn(3)]
+		}
+		return string(b)
+	}
+	for round := 0; round < 200; round++ {
+		var keywords []string
+		for i := 0; i < 1+rnd.Intn(6); i++ {
+			keywords = append(keywords, randString(1+rnd.Intn(4)))
+		}
+		text := randString(rnd.Intn(30))
+
+		kp := NewKeywordProcessor(WithMatchKind(LeftmostLongest))
+		kp.AddKeywordsFromList(keywords).Build()
+		got := matchStrings(kp.ExtractKeywords(text))
+		kp.Close()
+
+		var want []Match
+		for pos := 0; pos < len(text); {
+			best := ""
+			for _, k := range keywords {
+				if strings.HasPrefix(text[pos:], k) && len(k) > len(best) {
+					best = k
+				}
+			}
+			if best == "" {
+				pos++
+				continue
+			}
+			want = append(want, Match{match: best, start: pos, end: pos + len(best)})
+			pos += len(best)
+		}
+		if w := matchStrings(want); got != w {
+			t.Fatalf("关键词 %v 文本 '%s': 期望 '%s', 实际 '%s'", keywords, text, w, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package flashtext
+
+import "sort"
+
+// MatchOrder selects the order of the matches returned in a slice by
+// ExtractKeywords, ExtractKeywordsFromBytes and AppendMatches. Ties not
+// resolved by the order keep the default order. FindAll, Stream and
+// ExtractFromReader report matches as they are found, in the default order.
+type MatchOrder int
+
+const (
+	// OrderByEnd orders matches by end offset, and those ending at the same
+	// offset longest first. It is the order the automaton finds them in and
+	// does not depend on the order the keywords were added in.
+	OrderByEnd MatchOrder = iota
+	// OrderByStart orders matches by start offset, and those starting at the
+	// same offset longest first.
+	OrderByStart
+	// OrderByLength orders matches longest first, counting the runes of the
+	// keyword, and those of the same length by start offset.
+	OrderByLength
+	// OrderByInsertion orders matches by the order their keywords were added
+	// in, and the matches of one keyword by start offset.
+	OrderByInsertion
+)
+
+func (o MatchOrder) String() string {
+	switch o {
+	case OrderByEnd:
+		return "OrderByEnd"
+	case OrderByStart:
+		return "OrderByStart"
+	case OrderByLength:
+		return "OrderByLength"
+	case OrderByInsertion:
+		return "OrderByInsertion"
+	default:
+		return "MatchOrder(?)"
+	}
+}
+
+// sorted reports whether matches in the default order are already in the
+// automaton's MatchOrder.
+func (a *Automaton) sorted() bool {
+	// 不重叠的匹配按结尾排序也就是按开头排序
+	return a.matchOrder == OrderByEnd || a.matchOrder == OrderByStart && a.matchKind != MatchAll
+}
+
+// sortMatches puts the n matches returned by at, found in the default order,
+// into the automaton's MatchOrder. swap exchanges two of them.
+func (a *Automaton) sortMatches(n int, at func(i int) *Match, swap func(i, j int)) {
+	sort.Stable(matchSorter{a, n, at, swap})
+}
+
+type matchSorter struct {
+	a    *Automaton
+	n    int
+	at   func(i int) *Match
+	swap func(i, j int)
+}
+
+func (ms matchSorter) Len() int {
+	return ms.n
+}
+
+func (ms matchSorter) Less(i, j int) bool {
+	x, y := ms.at(i), ms.at(j)
+	switch ms.a.matchOrder {
+	case OrderByStart:
+		if x.start != y.start {
+			return x.start < y.start
+		}
+		return ms.a.entries[x.id].length > ms.a.entries[y.id].length
+	case OrderByLength:
+		if lx, ly := ms.a.entries[x.id].length, ms.a.entries[y.id].length; lx != ly {
+			return lx > ly
+		}
+		return x.start < y.start
+	case OrderByInsertion:
+		if x.id != y.id {
+			return x.id < y.id
+		}
+		return x.start < y.start
+	}
+	return false
+}
+
+func (ms matchSorter) Swap(i, j int) {
+	ms.swap(i, j)
+}
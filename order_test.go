@@ -0,0 +1,51 @@
+package flashtext
+
+import "testing"
+
+// 返回切片的方法按选择的顺序排列匹配
+func TestMatchOrderOptions(t *testing.T) {
+	keywords := []string{"cd", "abcd", "b", "bc"}
+	text := "abcd bc"
+	tests := []struct {
+		order MatchOrder
+		kind  MatchKind
+		want  string
+	}{
+		{OrderByEnd, MatchAll, "b[1:2] bc[1:3] abcd[0:4] cd[2:4] b[5:6] bc[5:7]"},
+		{OrderByStart, MatchAll, "abcd[0:4] bc[1:3] b[1:2] cd[2:4] bc[5:7] b[5:6]"},
+		{OrderByLength, MatchAll, "abcd[0:4] bc[1:3] cd[2:4] bc[5:7] b[1:2] b[5:6]"},
+		{OrderByInsertion, MatchAll, "cd[2:4] abcd[0:4] b[1:2] b[5:6] bc[1:3] bc[5:7]"},
+		{OrderByStart, LeftmostLongest, "abcd[0:4] bc[5:7]"},
+		{OrderByInsertion, LeftmostLongest, "abcd[0:4] bc[5:7]"},
+		{OrderByInsertion, LeftmostFirst, "abcd[0:4] b[5:6]"},
+	}
+	for _, tt := range tests {
+		kp := NewKeywordProcessor(WithMatchOrder(tt.order), WithMatchKind(tt.kind))
+		kp.AddKeywordsFromList(keywords)
+		if s := matchStrings(kp.ExtractKeywords(text)); s != tt.want {
+			t.Errorf("%v %v: 期望 '%s', 实际 '%s'", tt.order, tt.kind, tt.want, s)
+		}
+		if s := matchStrings(kp.ExtractKeywordsFromBytes([]byte(text))); s != tt.want {
+			t.Errorf("%v %v 字节数组: 期望 '%s', 实际 '%s'", tt.order, tt.kind, tt.want, s)
+		}
+		// 只排序追加的部分
+		dst := kp.AppendMatches([]Match{{match: "x"}}, text)
+		if s := matchStrings(dst[1:]); dst[0].match != "x" || s != tt.want {
+			t.Errorf("%v %v 追加: 期望 '%s', 实际 '%s'", tt.order, tt.kind, tt.want, s)
+		}
+		kp.Close()
+	}
+
+	tp := NewTypedProcessor[int](WithMatchOrder(OrderByLength))
+	defer tp.Close()
+	for i, k := range keywords {
+		tp.Add(k, i)
+	}
+	var got []Match
+	for _, m := range tp.ExtractKeywords(text) {
+		got = append(got, m.Match)
+	}
+	if s := matchStrings(got); s != tests[2].want {
+		t.Errorf("TypedProcessor: 期望 '%s', 实际 '%s'", tests[2].want, s)
+	}
+}
@@ -0,0 +1,90 @@
+package flashtext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// prefilter lets the scanner skip, while at the root, the bytes that cannot
+// start a keyword: the first byte of a rune that no keyword starts with, in
+// any case if matching ignores case. A single candidate byte is searched with
+// strings.IndexByte, several with a table.
+//
+// Only bytes that are ASCII or UTF-8 lead bytes, or invalid bytes read as
+// themselves, are candidates, so the scanner always lands on the start of a
+// rune.
+type prefilter struct {
+	first [256]bool // 可能开始一个关键词的字节
+	n     int       // 候选字节的数量
+	only  byte      // n == 1 时唯一的候选字节
+}
+
+// newPrefilter returns the prefilter for keywords starting with the runes
+// firsts, lowercased unless caseSensitive, or nil if it cannot be used: in
+// InvalidUTF8Fail mode, where every byte must be checked, and when a keyword
+// starts with an invalid continuation byte, which may lie inside a rune.
+func newPrefilter(firsts []rune, caseSensitive bool, mode InvalidUTF8Mode) *prefilter {
+	if len(firsts) == 0 || mode == InvalidUTF8Fail {
+		return nil
+	}
+	p := &prefilter{}
+	var buf [utf8.UTFMax]byte
+	add := func(r rune) bool {
+		var b byte
+		if r > utf8.MaxRune {
+			// 无效字节按自身读取
+			b = byte(r - byteRune(0))
+			if !utf8.RuneStart(b) {
+				return false
+			}
+		} else {
+			utf8.EncodeRune(buf[:], r)
+			b = buf[0]
+		}
+		if !p.first[b] {
+			p.first[b] = true
+			p.n++
+			p.only = b
+		}
+		return true
+	}
+	for _, r := range firsts {
+		if !add(r) {
+			return nil
+		}
+	}
+	if !caseSensitive {
+		// 转为小写后是某个首字符的字符都可能开始匹配，它们都在 CaseRanges 中
+		set := make(map[rune]bool, len(firsts))
+		for _, r := range firsts {
+			set[r] = true
+		}
+		for _, cr := range unicode.CaseRanges {
+			if cr.Delta[unicode.LowerCase] == 0 {
+				continue
+			}
+			for r := rune(cr.Lo); r <= rune(cr.Hi); r++ {
+				if set[unicode.ToLower(r)] {
+					add(r)
+				}
+			}
+		}
+	}
+	return p
+}
+
+// skip returns the offset of the first candidate byte in text from i on,
+// or len(text) if there is none.
+func (p *prefilter) skip(text string, i int) int {
+	if p.n == 1 {
+		if j := strings.IndexByte(text[i:], p.only); j >= 0 {
+			return i + j
+		}
+		return len(text)
+	}
+	for i < len(text) && !p.first[text[i]] {
+		i++
+	}
+	return i
+}
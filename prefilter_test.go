@@ -0,0 +1,78 @@
+package flashtext
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// 跳过字节前后的匹配结果一致
+func TestPrefilter(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	alphabet := []string{"a", "b", "x", "A", "K", "K", "İ", "i", "中", "文", "\xff", "\xc3", "\x85", " "}
+	randString := func(n int) string {
+		s := ""
+		for i := 0; i < n; i++ {
+			s += alphabet[rnd.Intn(len(alphabet))]
+		}
+		return s
+	}
+	for round := 0; round < 500; round++ {
+		var keywords []string
+		for i := 0; i < 1+rnd.Intn(4); i++ {
+			keywords = append(keywords, randString(1+rnd.Intn(3)))
+		}
+		opts := []Option{WithMatchKind(MatchKind(round % 3)), WithInvalidUTF8(InvalidUTF8Mode(round / 3 % 2))}
+		if round%5 == 0 {
+			opts = append(opts, WithCaseSensitive())
+		}
+		if round%7 == 0 {
+			opts = append(opts, WithWordBoundaries())
+		}
+		kp := NewKeywordProcessor(opts...)
+		a := kp.AddKeywordsFromList(keywords).Build()
+		text := randString(rnd.Intn(40))
+
+		got := matchStrings(a.ExtractKeywords(text))
+		plain := *a
+		plain.skip = nil
+		if want := matchStrings(plain.ExtractKeywords(text)); got != want {
+			t.Fatalf("关键词 %q 文本 %q: 期望 '%s', 实际 '%s'", keywords, text, want, got)
+		}
+		kp.Close()
+	}
+}
+
+// 不区分大小写时，转为小写后是首字符的字符也是候选
+func TestPrefilterCaseFolding(t *testing.T) {
+	tests := []struct {
+		keyword string
+		text    string
+		want    string
+	}{
+		{"kb", "1 KB 2 Kb", "KB[2:4] Kb[7:11]"},
+		{"is", "this İs", "is[2:4] İs[5:8]"},
+		{"中文", "英文中文", "中文[6:12]"},
+	}
+	for _, tt := range tests {
+		kp := NewKeywordProcessor()
+		a := kp.AddKeyWord(tt.keyword).Build()
+		if a.skip == nil {
+			t.Fatal("应使用跳过字节的预过滤")
+		}
+		if s := matchStrings(a.ExtractKeywords(tt.text)); s != tt.want {
+			t.Errorf("关键词 '%s': 期望 '%s', 实际 '%s'", tt.keyword, tt.want, s)
+		}
+		kp.Close()
+	}
+
+	// 无效 UTF-8 报错或关键词以续字节开头时不使用
+	for _, kp := range []*KeywordProcessor{
+		NewKeywordProcessor(WithInvalidUTF8(InvalidUTF8Fail)).AddKeyWord("ab"),
+		NewKeywordProcessor().AddKeyWord("\x85ab"),
+	} {
+		if kp.Build().skip != nil {
+			t.Error("不应使用预过滤")
+		}
+		kp.Close()
+	}
+}
@@ -0,0 +1,128 @@
+package flashtext
+
+import "math/bits"
+
+// nodeBlock marks which of 64 consecutive RadixTrie states are nodes.
+type nodeBlock struct {
+	bits uint64 // 第 i 位表示第 i 个状态是节点
+	rank int32  // 这 64 个状态之前的节点数
+}
+
+// numberRadix numbers the states run by run and fills labels and the
+// RadixTrie transitions. It returns the nodes and the parents of the states,
+// and the states in breadth-first order.
+//
+// A chain of states with a single child, as spelled by the tail of a long
+// keyword, takes consecutive numbers and is an edge labelled with the run of
+// codes labels[s+1], labels[s+2], ... Only the nodes, that is the root, the
+// states with no or several children and the states that do not follow their
+// parent, keep a depth and a child table. A state inside a run keeps just its
+// code, failure link and output, so that a mismatch in the middle of a run is
+// handled as in the other backends. The runs are numbered in breadth-first
+// order of their first state, so that the shallow states, where matching
+// spends most of its time, stay close together.
+func (a *Automaton) numberRadix(root *Node) (nodes []*Node, parents, order []int32) {
+	type item struct {
+		node   *Node
+		parent int32
+		code   int32
+		depth  int32
+		slot   int32 // 在父节点子节点表中的下标，-1 表示父节点在链中
+	}
+	a.labels = nil
+	a.edgeStart = []int32{0}
+	var edges []edge
+	queue := []item{{root, 0, 0, 0, -1}}
+	for q := 0; q < len(queue); q++ {
+		// 从队列中取出一条链的开头，连续编号直到分支或叶子
+		for it := queue[q]; ; {
+			s := int32(len(nodes))
+			nodes = append(nodes, it.node)
+			parents = append(parents, it.parent)
+			a.labels = append(a.labels, it.code)
+			if it.slot >= 0 {
+				a.edgeNext[it.slot] = s
+			}
+			if s&63 == 0 {
+				a.nodeBlocks = append(a.nodeBlocks, nodeBlock{rank: int32(len(a.nodeDepth))})
+			}
+
+			edges = a.alphabet.sortedEdges(edges, it.node)
+			if s > 0 && len(edges) == 1 && it.parent == s-1 {
+				// 链中的状态：唯一的子节点是下一个状态
+				it = item{edges[0].node, s, edges[0].code, it.depth + 1, -1}
+				continue
+			}
+			// 根、分支、叶子和链的开头是节点
+			a.nodeBlocks[s>>6].bits |= 1 << (s & 63)
+			a.nodeDepth = append(a.nodeDepth, it.depth)
+			lo := int32(len(a.edgeLabels))
+			for _, e := range edges {
+				a.edgeLabels = append(a.edgeLabels, e.code)
+				a.edgeNext = append(a.edgeNext, 0) // 子节点编号时填写
+			}
+			a.edgeStart = append(a.edgeStart, int32(len(a.edgeLabels)))
+			if s > 0 && len(edges) == 1 {
+				// 链的开头，唯一的子节点紧随其后
+				it = item{edges[0].node, s, edges[0].code, it.depth + 1, lo}
+				continue
+			}
+			for i, e := range edges {
+				queue = append(queue, item{e.node, s, e.code, it.depth + 1, lo + int32(i)})
+			}
+			break
+		}
+	}
+
+	order = make([]int32, 1, len(nodes))
+	for i := 0; i < len(order); i++ {
+		s := order[i]
+		if k, ok := a.radixNode(s); ok {
+			order = append(order, a.edgeNext[a.edgeStart[k]:a.edgeStart[k+1]]...)
+		} else {
+			order = append(order, s+1)
+		}
+	}
+	return nodes, parents, order
+}
+
+// radixNode returns the index of state s among the nodes, and false if s is
+// inside a run.
+func (a *Automaton) radixNode(s int32) (int32, bool) {
+	b, bit := &a.nodeBlocks[s>>6], uint64(1)<<(s&63)
+	if b.bits&bit == 0 {
+		return 0, false
+	}
+	return b.rank + int32(bits.OnesCount64(b.bits&(bit-1))), true
+}
+
+// radixChild returns the child of state s on code c in the RadixTrie
+// transitions, or -1.
+func (a *Automaton) radixChild(s, c int32) int32 {
+	k, ok := a.radixNode(s)
+	if !ok {
+		if a.labels[s+1] == c {
+			return s + 1
+		}
+		return -1
+	}
+	if i := searchCode(a.edgeLabels, a.edgeStart[k], a.edgeStart[k+1], c); i >= 0 {
+		return a.edgeNext[i]
+	}
+	return -1
+}
+
+// radixDepth returns the depth of state s from that of the node starting its
+// run: the states between them are consecutive, one rune deeper each.
+func (a *Automaton) radixDepth(s int32) int32 {
+	// 不晚于 s 的最后一个节点，根节点总是节点
+	i := s >> 6
+	w := a.nodeBlocks[i].bits & (uint64(2)<<(s&63) - 1)
+	for w == 0 {
+		i--
+		w = a.nodeBlocks[i].bits
+	}
+	p := i<<6 + int32(63-bits.LeadingZeros64(w))
+	k := a.nodeBlocks[i].rank + int32(bits.OnesCount64(w)) - 1
+	return a.nodeDepth[k] + s - p
+}
@@ -0,0 +1,83 @@
+package flashtext
+
+import (
+	"math/bits"
+	"strings"
+	"testing"
+)
+
+// 单子节点链压缩为一条边，只有节点保存深度和子节点表，链中途失配时沿失败指针继续
+func TestRadixTrie(t *testing.T) {
+	kp := NewKeywordProcessor(WithBackend(RadixTrie))
+	defer kp.Close()
+	a := kp.AddKeywordsFromList([]string{"https://a.com/x", "https://a.com/y", "a.com/z", "com"}).Build()
+
+	nodes := 0
+	for _, b := range a.nodeBlocks {
+		nodes += bits.OnesCount64(b.bits)
+	}
+	// 根、根的后两个子节点、"https://a.com/" 之后的分支和 4 个叶子
+	if len(a.labels) != 27 || nodes != 8 || len(a.nodeDepth) != 8 {
+		t.Errorf("期望 27 个状态、8 个节点, 实际 %d 个状态、%d 个节点", len(a.labels), nodes)
+	}
+	if a.depth != nil {
+		t.Error("链中的状态不应保存深度")
+	}
+
+	want := "com[10:13] https://a.com/y[0:15] com[26:29] a.com/z[24:31]"
+	if s := matchStrings(a.ExtractKeywords("https://a.com/y https://a.com/z")); s != want {
+		t.Errorf("期望 '%s', 实际 '%s'", want, s)
+	}
+	if a.Contains("https://a.com") || !a.Contains("a.com/z") {
+		t.Error("链中的状态不是关键词")
+	}
+}
+
+// 跨越多个 64 位字的长链，最左匹配依赖链中状态的深度
+func TestRadixTrieLongRun(t *testing.T) {
+	long := strings.Repeat("ab", 100) + "c"
+	kp := NewKeywordProcessor(WithBackend(RadixTrie), WithMatchKind(LeftmostLongest))
+	defer kp.Close()
+	a := kp.AddKeywordsFromList([]string{long, "ba", "bab"}).Build()
+
+	for s := int32(0); s < int32(len(a.labels)); s++ {
+		if s > 0 && a.fail[s] != 0 && a.depthOf(a.fail[s]) >= a.depthOf(s) {
+			t.Fatalf("状态 %d 的失败状态应更浅", s)
+		}
+	}
+	text := strings.Repeat("ab", 100) + "d " + long
+	want := "bab[1:4] bab[5:8] bab[9:12] bab[13:16] bab[17:20]"
+	got := matchStrings(a.ExtractKeywords(text))
+	if !strings.HasPrefix(got, want) || !strings.HasSuffix(got, long+"[202:403]") {
+		t.Errorf("期望以 '%s' 开头、以 '%s[202:403]' 结尾, 实际 '%s'", want, long, got)
+	}
+	if !a.Contains(long) || a.Contains(long[:len(long)-1]) {
+		t.Error("长链中的状态不是关键词")
+	}
+}
+
+// 分支节点有 26 个子节点，子节点表需要二分查找
+func TestRadixTrieWideNode(t *testing.T) {
+	var keywords []string
+	for c := 'a'; c <= 'z'; c++ {
+		keywords = append(keywords, "sku-"+string(c)+"-0042", string(c)+"-00")
+	}
+	kp := NewKeywordProcessor(WithBackend(RadixTrie))
+	defer kp.Close()
+	a := kp.AddKeywordsFromList(keywords).Build()
+
+	k, ok := a.radixNode(a.find("sku-"))
+	if !ok || a.edgeStart[k+1]-a.edgeStart[k] != 26 {
+		t.Fatal("'sku-' 之后应是有 26 个子节点的分支")
+	}
+	text := strings.Join(keywords, " ") + " sku-q-0041"
+	want := bruteForce(keywords, text, false, MatchAll)
+	if got := matchStrings(sortedMatches(a.ExtractKeywords(text))); got != want {
+		t.Errorf("期望 '%s', 实际 '%s'", want, got)
+	}
+	for _, k := range keywords {
+		if !a.Contains(k) {
+			t.Errorf("应包含关键词 '%s'", k)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package flashtext
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactOptions controls how Redact masks matched keywords.
+type RedactOptions struct {
+	Mask        rune   // 逐字符掩码，默认 '*'
+	Replacement string // 非空时将被掩盖的部分整体替换为该字符串，如 "[REDACTED]"
+	KeepFirst   int    // 保留每段开头的 rune 数
+	KeepLast    int    // 保留每段结尾的 rune 数
+}
+
+// DefaultMask is the mask rune used when RedactOptions.Mask is zero.
+const DefaultMask = '*'
+
+// Redact masks every keyword found in text according to opts.
+// All matches are considered whatever the automaton's MatchKind: overlapping or
+// adjacent matches are merged and the union of their spans is masked as one
+// segment. Lengths are counted in runes, so a CJK keyword of three characters
+// becomes three mask runes. A segment not longer than KeepFirst+KeepLast is
+// masked entirely.
+func (a *Automaton) Redact(text string, opts RedactOptions) string {
+	spans := mergeSpans(a.extractKeywords(text, MatchAll))
+	if len(spans) == 0 {
+		return text
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(text))
+	last := 0
+	for _, sp := range spans {
+		sb.WriteString(text[last:sp.start])
+		opts.write(&sb, text[sp.start:sp.end])
+		last = sp.end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// Redact masks every keyword found in text according to opts.
+// See Automaton.Redact.
+func (kp *KeywordProcessor) Redact(text string, opts RedactOptions) string {
+	return kp.automaton().Redact(text, opts)
+}
+
+// span is a half-open byte range [start, end).
+type span struct {
+	start int
+	end   int
+}
+
+// mergeSpans returns the union of the match spans, ordered by start position.
+func mergeSpans(matches []Match) []span {
+	if len(matches) == 0 {
+		return nil
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+	spans := make([]span, 0, len(matches))
+	cur := span{matches[0].start, matches[0].end}
+	for _, m := range matches[1:] {
+		if m.start <= cur.end {
+			if m.end > cur.end {
+				cur.end = m.end
+			}
+			continue
+		}
+		spans = append(spans, cur)
+		cur = span{m.start, m.end}
+	}
+	return append(spans, cur)
+}
+
+// write writes the masked form of segment to sb.
+func (opts RedactOptions) write(sb *strings.Builder, segment string) {
+	keepFirst, keepLast := opts.keep(utf8.RuneCountInString(segment))
+
+	i := 0
+	for ; keepFirst > 0; keepFirst-- {
+		_, size := utf8.DecodeRuneInString(segment[i:])
+		i += size
+	}
+	j := len(segment)
+	for ; keepLast > 0; keepLast-- {
+		_, size := utf8.DecodeLastRuneInString(segment[:j])
+		j -= size
+	}
+
+	sb.WriteString(segment[:i])
+	if opts.Replacement != "" {
+		sb.WriteString(opts.Replacement)
+	} else {
+		mask := opts.maskRune()
+		for k := utf8.RuneCountInString(segment[i:j]); k > 0; k-- {
+			sb.WriteRune(mask)
+		}
+	}
+	sb.WriteString(segment[j:])
+}
+
+// keep returns how many runes are kept at each end of a segment of n runes.
+func (opts RedactOptions) keep(n int) (first, last int) {
+	if opts.KeepFirst < 0 || opts.KeepLast < 0 || opts.KeepFirst+opts.KeepLast >= n {
+		return 0, 0
+	}
+	return opts.KeepFirst, opts.KeepLast
+}
+
+func (opts RedactOptions) maskRune() rune {
+	if opts.Mask == 0 {
+		return DefaultMask
+	}
+	return opts.Mask
+}
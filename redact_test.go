@@ -0,0 +1,35 @@
+package flashtext
+
+import "testing"
+
+// 敏感词掩码测试
+func TestRedact(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"he", "she", "hers", "password", "毛泽东", "东方"}).Build()
+
+	tests := []struct {
+		name     string
+		text     string
+		opts     RedactOptions
+		expected string
+	}{
+		{"默认掩码", "my password is secret", RedactOptions{}, "my ******** is secret"},
+		{"自定义掩码", "my password", RedactOptions{Mask: '#'}, "my ########"},
+		{"固定替换", "my password", RedactOptions{Replacement: "[REDACTED]"}, "my [REDACTED]"},
+		{"保留首尾", "my password", RedactOptions{KeepFirst: 1, KeepLast: 2}, "my p*****rd"},
+		{"过短全掩", "she", RedactOptions{KeepFirst: 2, KeepLast: 1}, "***"},
+		{"重叠合并", "hershey", RedactOptions{}, "******y"},
+		{"重叠合并替换", "hershey", RedactOptions{Replacement: "[X]"}, "[X]y"},
+		{"中文按字符计数", "毛泽东方红", RedactOptions{}, "****红"},
+		{"中文保留首字", "毛泽东", RedactOptions{KeepFirst: 1, Mask: '*'}, "毛**"},
+		{"无匹配", "nothing", RedactOptions{}, "nothing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kp.Redact(tt.text, tt.opts); got != tt.expected {
+				t.Errorf("期望 '%s', 实际 '%s'", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package flashtext
+
+import (
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+// ErrWriterClosed is returned by RedactingWriter.Write after Close.
+var ErrWriterClosed = errors.New("flashtext: write to closed RedactingWriter")
+
+// RedactingWriter masks keywords in everything written to it before passing
+// it on to the underlying writer, e.g. a log sink or an HTTP response body.
+// Once closed, the bytes written downstream are exactly Redact applied to the
+// whole input, including matches straddling two Writes.
+//
+// Only the input that may still be part of a match is held back: the last few
+// runes, as many as the longest keyword, plus up to KeepFirst+KeepLast runes
+// of the segment being masked. Everything else is passed on by the Write that
+// brought it, so memory stays bounded whatever the input size.
+//
+// A RedactingWriter is not safe for concurrent use. Close must be called to
+// write out the held back tail; it does not close the underlying writer.
+type RedactingWriter struct {
+	w       io.Writer
+	opts    RedactOptions
+	scanner scanner
+	buf     []byte // 从 base 开始尚未写出或仍需保留的输入
+	base    int
+	out     int    // 之前的输入都已写出
+	spans   []span // 尚未写完的掩码区间，按起点排序
+	masked  int    // spans[0] 中已写出的 rune 数
+	dst     []byte // 待写入 w 的结果
+	err     error  // w 返回的第一个错误或无效 UTF-8 错误
+	closed  bool
+}
+
+// NewRedactingWriter returns a RedactingWriter writing to w the input with
+// every keyword of kp masked according to opts. It uses the keywords kp holds
+// when it is created. See Automaton.Redact.
+func NewRedactingWriter(w io.Writer, kp *KeywordProcessor, opts RedactOptions) *RedactingWriter {
+	rw := &RedactingWriter{w: w, opts: opts}
+	rw.scanner.reset(kp.automaton(), MatchAll)
+	return rw
+}
+
+// Write masks p and writes out everything that can no longer be part of a
+// match. The rest is held back until a later Write or Close. It always
+// consumes the whole of p. The error is the one returned by the underlying
+// writer, or an *InvalidUTF8Error in InvalidUTF8Fail mode, after which every
+// Write fails.
+func (rw *RedactingWriter) Write(p []byte) (int, error) {
+	if rw.closed {
+		return 0, ErrWriterClosed
+	}
+	if rw.err != nil {
+		return 0, rw.err
+	}
+	rw.buf = append(rw.buf, p...)
+	rw.advance(false)
+	return len(p), rw.flush()
+}
+
+// Close marks the end of the input and writes out what was held back.
+// Closing twice has no further effect.
+func (rw *RedactingWriter) Close() error {
+	if rw.closed || rw.err != nil {
+		rw.closed = true
+		return rw.err
+	}
+	rw.closed = true
+	rw.advance(true)
+	return rw.flush()
+}
+
+// advance scans the buffered input, produces the output that is final and
+// drops the bytes no longer needed.
+func (rw *RedactingWriter) advance(final bool) {
+	text := bytesToString(rw.buf)
+	if !rw.scanner.scan(text, rw.base, final, rw.add) {
+		// 无效 UTF-8 之前的内容照常写出
+		rw.err = rw.scanner.err
+		final = true
+	}
+
+	// 当前节点拼出最后 depth 个 rune，之后的匹配不会从更早的位置开始
+	safe := rw.scanner.pos
+	if !final {
+		safe = rw.base + rw.scanner.runeStart(text[:safe-rw.base], int(rw.scanner.a.depthOf(rw.scanner.state)))
+	}
+	rw.emit(text, safe, final)
+
+	keep := rw.scanner.retain(text, rw.base)
+	if rw.out < keep {
+		keep = rw.out
+	}
+	keep -= rw.base
+	rw.buf = rw.buf[:copy(rw.buf, rw.buf[keep:])]
+	rw.base += keep
+}
+
+// add merges the match [start, end) into the pending spans.
+func (rw *RedactingWriter) add(start, end, _ int) bool {
+	i := 0
+	for i < len(rw.spans) && rw.spans[i].end < start {
+		i++
+	}
+	j := i
+	for ; j < len(rw.spans) && rw.spans[j].start <= end; j++ {
+		if rw.spans[j].start < start {
+			start = rw.spans[j].start
+		}
+		if rw.spans[j].end > end {
+			end = rw.spans[j].end
+		}
+	}
+	if i == j {
+		rw.spans = append(rw.spans, span{})
+		copy(rw.spans[i+1:], rw.spans[i:])
+	} else {
+		rw.spans = append(rw.spans[:i+1], rw.spans[j:]...)
+	}
+	rw.spans[i] = span{start, end}
+	return true
+}
+
+// emit produces the output for the input before safe, which no later match
+// can start in. A span still open at safe may grow further, so only its part
+// that is known to be masked is written.
+func (rw *RedactingWriter) emit(text string, safe int, final bool) {
+	for len(rw.spans) > 0 {
+		sp := rw.spans[0]
+		if sp.start >= safe {
+			break
+		}
+		rw.plain(text, sp.start)
+		n := rw.masked + utf8.RuneCountInString(text[rw.out-rw.base:sp.end-rw.base])
+		if sp.end >= safe && !final {
+			keepFirst, keepLast := rw.opts.KeepFirst, rw.opts.KeepLast
+			if keepFirst < 0 || keepLast < 0 {
+				keepFirst, keepLast = 0, 0
+			}
+			// 段长超过首尾保留数后，是否整段掩盖已经确定
+			if n > keepFirst+keepLast {
+				rw.mask(text, n-keepLast, keepFirst)
+			}
+			return
+		}
+		keepFirst, keepLast := rw.opts.keep(n)
+		rw.mask(text, n-keepLast, keepFirst)
+		rw.plain(text, sp.end)
+		rw.spans = rw.spans[:copy(rw.spans, rw.spans[1:])]
+		rw.masked = 0
+	}
+	rw.plain(text, safe)
+}
+
+// plain copies the input up to the absolute offset to unchanged.
+func (rw *RedactingWriter) plain(text string, to int) {
+	if to > rw.out {
+		rw.dst = append(rw.dst, text[rw.out-rw.base:to-rw.base]...)
+		rw.out = to
+	}
+}
+
+// mask writes the runes of spans[0] up to the upto-th one, keeping the first
+// keepFirst of them as they are.
+func (rw *RedactingWriter) mask(text string, upto, keepFirst int) {
+	i := rw.out - rw.base
+	for ; rw.masked < upto; rw.masked++ {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case rw.masked < keepFirst:
+			rw.dst = append(rw.dst, text[i:i+size]...)
+		case rw.opts.Replacement == "":
+			rw.dst = utf8.AppendRune(rw.dst, rw.opts.maskRune())
+		case rw.masked == keepFirst:
+			rw.dst = append(rw.dst, rw.opts.Replacement...)
+		}
+		i += size
+	}
+	rw.out = rw.base + i
+}
+
+// flush writes the produced output to the underlying writer.
+func (rw *RedactingWriter) flush() error {
+	if len(rw.dst) > 0 {
+		_, err := rw.w.Write(rw.dst)
+		rw.dst = rw.dst[:0]
+		if err != nil {
+			rw.err = err
+		}
+	}
+	return rw.err
+}
@@ -0,0 +1,104 @@
+package flashtext
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// 分片写入与整体掩码结果一致
+func TestRedactingWriter(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"he", "she", "hers", "password", "毛泽东", "东方", "ab", "bcd"})
+	text := strings.Repeat("my password, ushers 毛泽东方红 hershey abcd xbcdy she. ", 20)
+
+	for _, opts := range []RedactOptions{
+		{},
+		{Mask: '#'},
+		{Replacement: "[REDACTED]"},
+		{KeepFirst: 1, KeepLast: 2},
+		{KeepFirst: 2, KeepLast: 1, Replacement: "[X]"},
+		{KeepFirst: -1, KeepLast: 3},
+	} {
+		want := kp.Redact(text, opts)
+		for round := 0; round < 10; round++ {
+			var out bytes.Buffer
+			rw := NewRedactingWriter(&out, kp, opts)
+			for rest := []byte(text); len(rest) > 0; {
+				n := 1 + rnd.Intn(9)
+				if n > len(rest) {
+					n = len(rest)
+				}
+				if _, err := rw.Write(rest[:n]); err != nil {
+					t.Fatalf("写入失败: %v", err)
+				}
+				rest = rest[n:]
+			}
+			if err := rw.Close(); err != nil {
+				t.Fatalf("关闭失败: %v", err)
+			}
+			if got := out.String(); got != want {
+				t.Fatalf("%+v: 期望 '%s', 实际 '%s'", opts, want, got)
+			}
+		}
+	}
+}
+
+// 连续相邻的匹配不会让缓冲无限增长，未匹配的内容及时写出
+func TestRedactingWriterBounded(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"ab", "token"})
+
+	var out bytes.Buffer
+	rw := NewRedactingWriter(&out, kp, RedactOptions{KeepFirst: 2, KeepLast: 2})
+	for i := 0; i < 1000; i++ {
+		rw.Write([]byte("abababab"))
+		if len(rw.buf) > 16 {
+			t.Fatalf("缓冲过大: %d 字节", len(rw.buf))
+		}
+	}
+	rw.Write([]byte(" plain text "))
+	if !strings.HasSuffix(out.String(), "ab plain text ") {
+		t.Errorf("未匹配内容未及时写出: '%s'", out.String()[out.Len()-20:])
+	}
+	rw.Write([]byte("tok"))
+	rw.Write([]byte("en"))
+	rw.Close()
+
+	want := "ab" + strings.Repeat("*", 8000-4) + "ab plain text to*en"
+	if out.String() != want {
+		t.Errorf("期望长度 %d 的掩码结果, 实际 '%s'", len(want), out.String()[len(want)-20:])
+	}
+	if _, err := rw.Write([]byte("x")); err != ErrWriterClosed {
+		t.Errorf("关闭后写入应返回 ErrWriterClosed, 实际 %v", err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+// 下游错误会被返回
+func TestRedactingWriterError(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeyWord("secret")
+
+	rw := NewRedactingWriter(failingWriter{}, kp, RedactOptions{})
+	if _, err := rw.Write([]byte("hello world")); err == nil {
+		t.Error("期望返回下游写入错误")
+	}
+	if _, err := rw.Write([]byte("again")); err == nil {
+		t.Error("出错后继续写入应返回错误")
+	}
+	if err := rw.Close(); err == nil {
+		t.Error("关闭时应返回下游写入错误")
+	}
+}
@@ -0,0 +1,50 @@
+package flashtext
+
+import "strings"
+
+// ReplaceKeywords replaces every keyword found in text with its clean name.
+// Overlapping matches are resolved leftmost-longest: the match starting first
+// wins, ties are broken by the longer match, and matches overlapping an already
+// chosen one are dropped, so the output is deterministic.
+// An automaton built with WithMatchKind(LeftmostFirst) resolves them leftmost-first instead.
+func (a *Automaton) ReplaceKeywords(text string) string {
+	return a.ReplaceKeywordsFunc(text, func(m Match) string {
+		return m.cleanName
+	})
+}
+
+// ReplaceKeywordsFunc is like ReplaceKeywords but replaces each chosen match
+// with the string returned by repl.
+func (a *Automaton) ReplaceKeywordsFunc(text string, repl func(m Match) string) string {
+	kind := a.matchKind
+	if kind == MatchAll {
+		kind = LeftmostLongest
+	}
+	matches := a.extractKeywords(text, kind)
+	if len(matches) == 0 {
+		return text
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(text))
+	last := 0
+	for _, m := range matches {
+		sb.WriteString(text[last:m.start])
+		sb.WriteString(repl(m))
+		last = m.end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// ReplaceKeywords replaces every keyword found in text with its clean name.
+// See Automaton.ReplaceKeywords.
+func (kp *KeywordProcessor) ReplaceKeywords(text string) string {
+	return kp.automaton().ReplaceKeywords(text)
+}
+
+// ReplaceKeywordsFunc replaces every keyword found in text with the string returned by repl.
+// See Automaton.ReplaceKeywordsFunc.
+func (kp *KeywordProcessor) ReplaceKeywordsFunc(text string, repl func(m Match) string) string {
+	return kp.automaton().ReplaceKeywordsFunc(text, repl)
+}
@@ -0,0 +1,45 @@
+package flashtext
+
+import "testing"
+
+// 关键词替换测试
+func TestReplaceKeywords(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordWithCleanName("NYC", "New York").
+		AddKeywordWithCleanName("New York City", "New York").
+		AddKeywordWithCleanName("New", "Old").
+		AddKeywordWithCleanName("毛泽东", "毛主席").
+		Build()
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"I love nyc!", "I love New York!"},
+		{"I love New York City!", "I love New York!"}, // 最长优先，不替换 "New"
+		{"New stuff", "Old stuff"},
+		{"毛泽东诗词", "毛主席诗词"},
+		{"nothing here", "nothing here"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := kp.ReplaceKeywords(tt.text); got != tt.expected {
+			t.Errorf("文本 '%s': 期望 '%s', 实际 '%s'", tt.text, tt.expected, got)
+		}
+	}
+}
+
+// 重叠匹配按最左最长原则替换
+func TestReplaceKeywordsOverlapping(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"he", "she", "hers"}).Build()
+
+	got := kp.ReplaceKeywordsFunc("hershey", func(m Match) string {
+		return "<" + m.MatchString() + ">"
+	})
+	if got != "<hers><he>y" {
+		t.Errorf("期望 '<hers><he>y', 实际 '%s'", got)
+	}
+}
@@ -0,0 +1,184 @@
+package flashtext
+
+import (
+	"unicode/utf8"
+	"unsafe"
+)
+
+// scanner runs an Automaton over text that may arrive in pieces.
+//
+// Matches are reported one rune late, once the rune following them is known,
+// so that word boundaries can be checked. For the leftmost match kinds the
+// best match seen so far is kept as pending. The current state spells the last
+// depth runes, so no later match can start before runes-depth; once
+// that bound passes the pending start, the pending match is final. It is
+// reported and scanning restarts from the root right after it, rescanning at
+// most the length of the longest keyword.
+type scanner struct {
+	a     *Automaton
+	kind  MatchKind
+	state int32
+	pos   int // 下一个待解码字节的绝对偏移
+	end   int // 最后一个已解码字符的结尾，跳过无效字节时小于 pos
+	runes int // end 之前已解码的 rune 数
+	mode  InvalidUTF8Mode
+	err   error
+
+	// 最左匹配策略下尚未确定的候选匹配
+	pending          int // entry id，-1 表示没有
+	pendingStart     int // 字节偏移
+	pendingEnd       int
+	pendingStartRune int // rune 序号
+	pendingEndRune   int
+}
+
+func (s *scanner) reset(a *Automaton, kind MatchKind) {
+	*s = scanner{a: a, kind: kind, mode: a.invalidUTF8, pending: -1}
+}
+
+// scan advances over text, which holds the input from the absolute offset
+// base on and must include every byte from s.pos on. Unless final, it stops
+// before a rune that is not complete yet, or that is the last one available,
+// and waits for the next call. emit receives absolute byte offsets. scan
+// returns false if emit asked to stop, or if it met invalid UTF-8 in
+// InvalidUTF8Fail mode, which it records in s.err.
+func (s *scanner) scan(text string, base int, final bool, emit func(start, end, id int) bool) bool {
+	a := s.a
+	for {
+		if s.state == 0 && s.pending < 0 && a.skip != nil {
+			// 在根节点且没有候选匹配，直接跳到下一个可能开始关键词的字节
+			s.pos = base + a.skip.skip(text, s.pos-base)
+		}
+		var r rune
+		size := 0
+		invalid := false
+		if i := s.pos - base; i < len(text) {
+			if c := text[i]; c < utf8.RuneSelf {
+				r, size = rune(c), 1
+			} else if final || utf8.FullRuneInString(text[i:]) {
+				r, size = utf8.DecodeRuneInString(text[i:])
+				if r == utf8.RuneError && size == 1 {
+					switch s.mode {
+					case InvalidUTF8Skip:
+						s.pos++
+						continue
+					case InvalidUTF8Fail:
+						// 当作输入在此结束，确定之前的匹配后报错
+						invalid, size = true, 0
+					default:
+						r = byteRune(c)
+					}
+				}
+			}
+		}
+		if size == 0 && !final && !invalid {
+			return true // 等待更多输入
+		}
+
+		// 当前状态的输出都在 s.end 结束，r 是紧随其后的字符
+		if a.out[s.state] >= 0 && !s.report(text, base, r, size > 0, emit) {
+			return false
+		}
+
+		if size == 0 {
+			// 输入结束，确定最后的候选后从其结尾继续
+			if s.pending < 0 {
+				if invalid {
+					s.err = &InvalidUTF8Error{Offset: s.pos}
+					return false
+				}
+				return true
+			}
+			if !s.commit(emit) {
+				return false
+			}
+			continue
+		}
+
+		s.state = a.next(s.state, r)
+		s.pos += size
+		s.end = s.pos
+		s.runes++
+		if s.pending >= 0 && s.runes-int(a.depthOf(s.state)) > s.pendingStartRune {
+			if !s.commit(emit) {
+				return false
+			}
+		}
+	}
+}
+
+// report handles the outputs of the current state, which end at s.end.
+// next is the rune after them, if hasNext.
+func (s *scanner) report(text string, base int, next rune, hasNext bool, emit func(start, end, id int) bool) bool {
+	a := s.a
+	end := s.end - base
+	// 同一结尾的关键词由长到短
+	for i := a.out[s.state]; i >= 0; i = a.suffix[i] {
+		id := int(i)
+		length := a.entries[id].length
+		if s.kind != MatchAll {
+			startRune := s.runes - length
+			if s.pending >= 0 && (startRune > s.pendingStartRune ||
+				startRune == s.pendingStartRune && !a.prefer(s.kind, id, s.pending)) {
+				continue
+			}
+		}
+		start := s.runeStart(text[:end], length)
+		if a.boundaries != nil && !a.boundaries.isWholeWord(text, start, end, next, hasNext) {
+			continue
+		}
+		if s.kind == MatchAll {
+			if !emit(base+start, base+end, id) {
+				return false
+			}
+			continue
+		}
+		s.pending = id
+		s.pendingStart, s.pendingEnd = base+start, base+end
+		s.pendingStartRune, s.pendingEndRune = s.runes-length, s.runes
+	}
+	return true
+}
+
+// commit reports the pending match and restarts from the root right after it.
+func (s *scanner) commit(emit func(start, end, id int) bool) bool {
+	id := s.pending
+	s.pending = -1
+	s.state = 0
+	s.pos, s.end, s.runes = s.pendingEnd, s.pendingEnd, s.pendingEndRune
+	return emit(s.pendingStart, s.pendingEnd, id)
+}
+
+// retain returns the absolute offset from which the input must be kept for
+// the next scan: the last maxLen+1 decoded runes, enough to find the start of
+// any match and the rune before it, and everything not decoded yet.
+func (s *scanner) retain(text string, base int) int {
+	return base + s.runeStart(text[:s.pos-base], s.a.maxLen+1)
+}
+
+// runeStart returns the offset in text of the n-th rune counted back from
+// its end, or 0 if text holds fewer runes. Invalid bytes count as one rune
+// each, or not at all when they are skipped.
+func (s *scanner) runeStart(text string, n int) int {
+	skip := s.mode == InvalidUTF8Skip
+	i := len(text)
+	for n > 0 && i > 0 {
+		if text[i-1] < utf8.RuneSelf {
+			i--
+			n--
+			continue
+		}
+		r, size := utf8.DecodeLastRuneInString(text[:i])
+		i -= size
+		if !skip || r != utf8.RuneError || size != 1 {
+			n--
+		}
+	}
+	return i
+}
+
+// bytesToString views b as a string without copying.
+// b must not be modified while the string is in use.
+func bytesToString(b []byte) string {
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
@@ -1,18 +1,15 @@
 package flashtext
 
 import (
-	"context"
 	"math"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
-	"time"
 )
 
 func TestStats_Init(t *testing.T) {
-	ctx := context.Background()
-	s := newStats(ctx, 0.2, 512)
-	defer s.close()
+	s := newStats(0.2)
 
 	if s == nil {
 		t.Fatal("stats should not be nil")
@@ -46,48 +43,39 @@ func TestStats_Integration_With_Processor(t *testing.T) {
 		t.Errorf("expected 1 match, got %d", len(matches))
 	}
 
-	// Allow time for async update
-	time.Sleep(50 * time.Millisecond)
-
 	// 3. Verify density updated
 	// New density = 1 match / 10 runes = 0.1
-	// EWMA = 0.2 * 0.1 + 0.8 * 0.0 = 0.02
+	// EWMA = 0.2 * 0.1 + 0.8 * 0.01 = 0.028
 	currentDensity := kp.stats.getDensity()
 	if currentDensity >= 0.1 {
 		t.Errorf("expected density < 0.1, got %v", currentDensity)
 	}
-	if math.Abs(currentDensity-0.02) > 0.01 {
-		t.Errorf("expected density ~0.02, got %v", currentDensity)
+	if math.Abs(currentDensity-0.028) > 1e-9 {
+		t.Errorf("expected density 0.028, got %v", currentDensity)
 	}
 }
 
 func TestStats_EWMA_Logic(t *testing.T) {
-	ctx := context.Background()
 	// Use alpha = 0.5 for easier calculation
-	s := newStats(ctx, 0.5, 512)
-	defer s.close()
+	s := newStats(0.5)
 
 	// Update 1: matches=10, runes=100 -> density=0.1
-	// EWMA = 0.5 * 0.1 + 0.5 * 0.0 = 0.05
+	// EWMA = 0.5 * 0.1 + 0.5 * 0.01 = 0.055
 	s.add(10, 100)
-	time.Sleep(50 * time.Millisecond)
-	if density := s.getDensity(); math.Abs(density-0.05) > 0.01 {
-		t.Errorf("expected density ~0.05, got %v", density)
+	if density := s.getDensity(); math.Abs(density-0.055) > 1e-9 {
+		t.Errorf("expected density 0.055, got %v", density)
 	}
 
 	// Update 2: matches=20, runes=100 -> density=0.2
-	// EWMA = 0.5 * 0.2 + 0.5 * 0.05 = 0.1 + 0.025 = 0.125
+	// EWMA = 0.5 * 0.2 + 0.5 * 0.055 = 0.1 + 0.0275 = 0.1275
 	s.add(20, 100)
-	time.Sleep(50 * time.Millisecond)
-	if density := s.getDensity(); math.Abs(density-0.125) > 0.01 {
-		t.Errorf("expected density ~0.125, got %v", density)
+	if density := s.getDensity(); math.Abs(density-0.1275) > 1e-9 {
+		t.Errorf("expected density 0.1275, got %v", density)
 	}
 }
 
 func TestStats_Concurrency(t *testing.T) {
-	ctx := context.Background()
-	s := newStats(ctx, 0.2, 512)
-	defer s.close()
+	s := newStats(0.2)
 
 	var wg sync.WaitGroup
 	count := 100
@@ -101,29 +89,36 @@ func TestStats_Concurrency(t *testing.T) {
 	}
 	wg.Wait()
 
-	// Give processor time to consume channel
-	time.Sleep(100 * time.Millisecond)
-
-	// Simply verify it didn't panic and density increased
-	if density := s.getDensity(); density <= 0.0 {
-		t.Errorf("expected density > 0.0, got %v", density)
+	// Concurrent updates may be dropped, but at least one is applied
+	// and the density stays between the old value and the observed one
+	if density := s.getDensity(); density <= 0.01 || density > 0.1 {
+		t.Errorf("expected density in (0.01, 0.1], got %v", density)
 	}
 }
 
 func TestStats_ZeroRunes(t *testing.T) {
-	ctx := context.Background()
-	s := newStats(ctx, 0.2, 512)
-	defer s.close()
+	s := newStats(0.2)
 
 	// Should not panic or update (division by zero protection)
 	initialDensity := s.getDensity()
 	s.add(1, 0)
-	time.Sleep(20 * time.Millisecond)
 	if density := s.getDensity(); density != initialDensity {
 		t.Errorf("expected density to remain %v, got %v", initialDensity, density)
 	}
 }
 
+func TestStats_NoGoroutine(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		// Processors are dropped without Close
+		kp := NewKeywordProcessor()
+		kp.AddKeyWord("apple").ExtractKeywords("apple pie")
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected no new goroutines, got %d more", after-before)
+	}
+}
+
 func TestFloat64BitsConversion(t *testing.T) {
 	val := 0.123456
 	bits := float64ToBits(val)
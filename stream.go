@@ -0,0 +1,170 @@
+package flashtext
+
+import (
+	"io"
+	"strings"
+)
+
+// readSize is the number of bytes a Stream asks for per Read.
+const readSize = 32 * 1024
+
+// Stream matches keywords in input fed piece by piece, e.g. payload fragments
+// received by a network proxy. It carries the automaton state between calls,
+// so matches spanning two pieces are found, runes split between pieces are
+// decoded correctly and Start/End are absolute byte offsets in the whole input.
+// Only the last few runes, as many as the longest keyword, are kept in memory.
+//
+// A Stream is not safe for concurrent use. After Flush it can be Reset and
+// reused, e.g. from a sync.Pool.
+type Stream struct {
+	load    func() *Automaton // Reset 时获取要使用的自动机
+	scanner scanner
+	fn      func(Match) bool
+	buf     []byte // 从 base 开始尚需保留的输入
+	base    int
+	done    bool  // 已 Flush、回调要求停止或遇到无效 UTF-8
+	err     error // InvalidUTF8Fail 模式下遇到的 *InvalidUTF8Error
+}
+
+// NewStream returns a Stream calling fn for each match selected by the
+// automaton's MatchKind, in the same order as ExtractKeywords.
+// Return false from fn to ignore the rest of the input.
+func (a *Automaton) NewStream(fn func(Match) bool) *Stream {
+	return newStream(func() *Automaton { return a }, fn)
+}
+
+// NewStream returns a Stream over the processor's current Automaton.
+// Reset switches the Stream to the Automaton current at that time.
+// See Automaton.NewStream.
+func (kp *KeywordProcessor) NewStream(fn func(Match) bool) *Stream {
+	return newStream(kp.automaton, fn)
+}
+
+// NewStream returns a Stream over the Automaton currently served.
+// Reset switches the Stream to the Automaton served at that time.
+// See Automaton.NewStream.
+func (hp *HotProcessor) NewStream(fn func(Match) bool) *Stream {
+	return newStream(hp.Automaton, fn)
+}
+
+func newStream(load func() *Automaton, fn func(Match) bool) *Stream {
+	st := &Stream{load: load}
+	st.Reset(fn)
+	return st
+}
+
+// Reset discards all input and state so that the Stream can be reused,
+// reporting matches to fn from now on.
+func (st *Stream) Reset(fn func(Match) bool) {
+	a := st.load()
+	st.scanner.reset(a, a.matchKind)
+	st.fn = fn
+	st.buf = st.buf[:0]
+	st.base = 0
+	st.done = false
+	st.err = nil
+}
+
+// Write feeds chunk to the Stream and reports the matches it completes.
+// Matches that may still grow or need the next rune are reported by a later
+// Write or by Flush. Write always consumes the whole chunk; input written after
+// Flush or after the callback returned false is ignored. The only error is an
+// *InvalidUTF8Error in InvalidUTF8Fail mode, returned again by later calls.
+func (st *Stream) Write(chunk []byte) (int, error) {
+	if !st.done {
+		st.buf = append(st.buf, chunk...)
+		st.advance(false)
+	}
+	return len(chunk), st.err
+}
+
+// ReadFrom feeds everything read from r to the Stream, reading directly into
+// its buffer. It stops early once the callback returns false. Like Write it
+// does not mark the end of the input, call Flush for that.
+// It returns the number of bytes read and the first read error other than
+// io.EOF, or the *InvalidUTF8Error met in InvalidUTF8Fail mode.
+func (st *Stream) ReadFrom(r io.Reader) (int64, error) {
+	var total int64
+	for !st.done {
+		if cap(st.buf)-len(st.buf) < readSize/2 {
+			grown := make([]byte, len(st.buf), 2*cap(st.buf)+readSize)
+			copy(grown, st.buf)
+			st.buf = grown
+		}
+		n, err := r.Read(st.buf[len(st.buf):cap(st.buf)])
+		st.buf = st.buf[:len(st.buf)+n]
+		total += int64(n)
+		if n > 0 {
+			st.advance(false)
+		}
+		if err == io.EOF {
+			return total, st.err
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, st.err
+}
+
+// Flush marks the end of the input and reports the remaining matches.
+// Like Write it returns the *InvalidUTF8Error met in InvalidUTF8Fail mode.
+func (st *Stream) Flush() error {
+	if !st.done {
+		st.advance(true)
+		st.done = true
+	}
+	return st.err
+}
+
+// advance scans the buffered input and drops the bytes no longer needed.
+func (st *Stream) advance(final bool) {
+	a := st.scanner.a
+	text := bytesToString(st.buf)
+	ok := st.scanner.scan(text, st.base, final, func(start, end, id int) bool {
+		if st.fn == nil {
+			return true
+		}
+		return st.fn(Match{
+			start:     start,
+			end:       end,
+			match:     strings.Clone(text[start-st.base : end-st.base]),
+			cleanName: a.entries[id].cleanName,
+			id:        id,
+		})
+	})
+	if !ok {
+		st.done = true
+		st.err = st.scanner.err
+		return
+	}
+
+	// 只保留后续匹配可能用到的字节
+	keep := st.scanner.retain(text, st.base) - st.base
+	st.buf = st.buf[:copy(st.buf, st.buf[keep:])]
+	st.base += keep
+}
+
+// ExtractFromReader searches for keywords in everything read from r and calls
+// fn for each match selected by the automaton's MatchKind, in the same order
+// as ExtractKeywords. Return false from fn to stop reading.
+//
+// The automaton state is kept across reads, so matches spanning two reads are
+// found, runes split between reads are decoded correctly and Start/End are
+// absolute byte offsets in the stream. Only the last few runes, as many as the
+// longest keyword, are kept in memory, so arbitrarily large inputs can be scanned.
+// The returned error is the first read error other than io.EOF, or an
+// *InvalidUTF8Error in InvalidUTF8Fail mode.
+func (a *Automaton) ExtractFromReader(r io.Reader, fn func(Match) bool) error {
+	st := a.NewStream(fn)
+	if _, err := st.ReadFrom(r); err != nil {
+		return err
+	}
+	return st.Flush()
+}
+
+// ExtractFromReader searches for keywords in everything read from r.
+// See Automaton.ExtractFromReader.
+func (kp *KeywordProcessor) ExtractFromReader(r io.Reader, fn func(Match) bool) error {
+	return kp.automaton().ExtractFromReader(r, fn)
+}
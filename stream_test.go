@@ -0,0 +1,185 @@
+package flashtext
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+func readerMatches(t *testing.T, kp *KeywordProcessor, r io.Reader) string {
+	t.Helper()
+	var matches []Match
+	if err := kp.ExtractFromReader(r, func(m Match) bool {
+		matches = append(matches, m)
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return matchStrings(matches)
+}
+
+// 流式提取与整体提取结果一致，包括跨块的匹配和被拆开的 rune
+func TestExtractFromReader(t *testing.T) {
+	options := map[string][]Option{
+		"全部":   nil,
+		"最左最长": {WithMatchKind(LeftmostLongest)},
+		"最左优先": {WithMatchKind(LeftmostFirst)},
+		"完整单词": {WithWordBoundaries()},
+	}
+	keywords := []string{"he", "she", "hers", "his", "毛泽东", "泽东", "AV演员", "AV", "a b"}
+	text := strings.Repeat("ahisHershare毛泽东dsa AV演员 a b hershey ", 50)
+
+	for name, opts := range options {
+		t.Run(name, func(t *testing.T) {
+			kp := NewKeywordProcessor(opts...)
+			defer kp.Close()
+			kp.AddKeywordsFromList(keywords)
+			want := matchStrings(kp.ExtractKeywords(text))
+
+			readers := map[string]func() io.Reader{
+				"整体": func() io.Reader { return strings.NewReader(text) },
+				"单字节": func() io.Reader {
+					return iotest.OneByteReader(strings.NewReader(text))
+				},
+				"半块": func() io.Reader {
+					return iotest.HalfReader(strings.NewReader(text))
+				},
+				"数据与EOF同时返回": func() io.Reader {
+					return iotest.DataErrReader(strings.NewReader(text))
+				},
+			}
+			for rname, newReader := range readers {
+				if got := readerMatches(t, kp, newReader()); got != want {
+					t.Errorf("%s: 期望 '%s', 实际 '%s'", rname, want, got)
+				}
+			}
+		})
+	}
+}
+
+// 随机分块读取
+func TestExtractFromReaderRandomChunks(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	kp := NewKeywordProcessor(WithMatchKind(LeftmostLongest))
+	defer kp.Close()
+	kp.AddKeywordsFromList([]string{"ab", "abcd", "bcx", "中文", "文字"})
+	text := strings.Repeat("xabcxabcd中文字ab", 20)
+	want := matchStrings(kp.ExtractKeywords(text))
+
+	for round := 0; round < 50; round++ {
+		r := &chunkReader{data: text, rnd: rnd}
+		if got := readerMatches(t, kp, r); got != want {
+			t.Fatalf("期望 '%s', 实际 '%s'", want, got)
+		}
+	}
+}
+
+type chunkReader struct {
+	data string
+	rnd  *rand.Rand
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := 1 + r.rnd.Intn(7)
+	if n > len(r.data) {
+		n = len(r.data)
+	}
+	n = copy(p, r.data[:n])
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// 读取错误与提前结束
+func TestExtractFromReaderStop(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeyWord("he")
+
+	n := 0
+	err := kp.ExtractFromReader(strings.NewReader("he he he"), func(m Match) bool {
+		n++
+		return false
+	})
+	if err != nil || n != 1 {
+		t.Errorf("期望停止于第 1 个匹配, 实际 %d 个, err=%v", n, err)
+	}
+
+	readErr := errors.New("boom")
+	err = kp.ExtractFromReader(iotest.ErrReader(readErr), func(m Match) bool { return true })
+	if !errors.Is(err, readErr) {
+		t.Errorf("期望读取错误 %v, 实际 %v", readErr, err)
+	}
+}
+
+// 分片写入与整体提取结果一致
+func TestStreamWrite(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	for _, kind := range []MatchKind{MatchAll, LeftmostLongest, LeftmostFirst} {
+		kp := NewKeywordProcessor(WithMatchKind(kind), WithWordBoundaries())
+		kp.AddKeywordsFromList([]string{"he", "she", "hers", "毛泽东", "泽东", "a b", "东 a"})
+		text := strings.Repeat("ushers 毛泽东 a b hershey she. ", 30)
+		want := matchStrings(kp.ExtractKeywords(text))
+
+		var got []Match
+		st := kp.NewStream(func(m Match) bool {
+			got = append(got, m)
+			return true
+		})
+		for round := 0; round < 20; round++ {
+			got = got[:0]
+			for rest := []byte(text); len(rest) > 0; {
+				n := 1 + rnd.Intn(9)
+				if n > len(rest) {
+					n = len(rest)
+				}
+				st.Write(rest[:n])
+				rest = rest[n:]
+			}
+			st.Flush()
+			if s := matchStrings(got); s != want {
+				t.Fatalf("%v: 期望 '%s', 实际 '%s'", kind, want, s)
+			}
+			st.Reset(st.fn)
+		}
+		kp.Close()
+	}
+}
+
+// 通过 sync.Pool 复用，Reset 后使用最新的自动机
+func TestStreamReset(t *testing.T) {
+	kp := NewKeywordProcessor()
+	defer kp.Close()
+	kp.AddKeyWord("apple")
+
+	pool := sync.Pool{New: func() any { return kp.NewStream(nil) }}
+	st := pool.Get().(*Stream)
+	var got []string
+	st.Reset(func(m Match) bool {
+		got = append(got, m.MatchString())
+		return true
+	})
+	st.Write([]byte("app"))
+	st.Write([]byte("le pie"))
+	st.Flush()
+	st.Write([]byte("apple")) // Flush 之后的输入被忽略
+	pool.Put(st)
+
+	kp.AddKeyWord("pie")
+	st = pool.Get().(*Stream)
+	st.Reset(func(m Match) bool {
+		got = append(got, m.MatchString())
+		return true
+	})
+	st.Write([]byte("apple pie"))
+	st.Flush()
+	if s := strings.Join(got, " "); s != "apple apple pie" {
+		t.Errorf("期望 'apple apple pie', 实际 '%s'", s)
+	}
+}
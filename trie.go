@@ -1,5 +1,7 @@
 package flashtext
 
+import "unicode"
+
 /*
 * @Author: zouyx
 * @Email:
@@ -9,8 +11,17 @@ package flashtext
 
 type Node struct {
 	children map[rune]*Node // 使用 map 存储叶子节点,key:'char' ,value: *Node
-	exist    []int          // 关键词节点是一个完整的匹配词，记录其长度  可以在build 的时候去重，匹配的时候遍历比map快
-	failure  *Node          // 记录失败指针
+	exist    []int          // 以该节点结尾的关键词 id（指向 KeywordProcessor.entries）
+	depth    int            // 节点深度，即从根节点到该节点的字符数
+}
+
+// entry describes a keyword registered in the processor.
+// Nodes refer to entries by their index, so a match knows which keyword it hit.
+type entry struct {
+	keyword   string // 原始关键词
+	cleanName string // 匹配后返回的标准名称，默认为关键词本身
+	length    int    // 关键词的 rune 长度
+	payload   any    // TypedProcessor 的附加数据
 }
 
 func newNode() *Node {
@@ -20,16 +31,51 @@ func newNode() *Node {
 	}
 }
 
+// find returns the node spelling keyword below n, or nil if there is none.
+func (n *Node) find(keyword string, caseSensitive bool) *Node {
+	node := n
+	for i := 0; i < len(keyword); {
+		char, size := decodeRune(keyword[i:])
+		i += size
+		if !caseSensitive {
+			char = unicode.ToLower(char)
+		}
+		node = node.children[char]
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// keywordAt returns the id of the keyword ending exactly at n, or -1.
+func (n *Node) keywordAt(entries []entry) int {
+	for _, id := range n.exist {
+		if entries[id].length == n.depth {
+			return id
+		}
+	}
+	return -1
+}
+
 type Match struct {
-	match string
-	start int
-	end   int
+	match     string
+	cleanName string
+	start     int
+	end       int
+	id        int // 关键词 id，即添加顺序
 }
 
 func (m *Match) MatchString() string {
 	return m.match
 }
 
+// CleanName returns the clean name of the matched keyword.
+// It is the keyword itself unless one was set with AddKeywordWithCleanName.
+func (m *Match) CleanName() string {
+	return m.cleanName
+}
+
 func (m *Match) Start() int {
 	return m.start
 }
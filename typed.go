@@ -0,0 +1,115 @@
+package flashtext
+
+import "unicode/utf8"
+
+// TypedProcessor is a KeywordProcessor whose keywords carry a payload of type T,
+// e.g. the category, severity and action attached to a banned term.
+// Matches return the payload directly, so no lookup by matched text is needed.
+type TypedProcessor[T any] struct {
+	kp *KeywordProcessor
+}
+
+// TypedMatch is a Match together with the payload of the matched keyword.
+type TypedMatch[T any] struct {
+	Match
+	payload T
+}
+
+// Payload returns the payload attached to the matched keyword.
+func (m *TypedMatch[T]) Payload() T {
+	return m.payload
+}
+
+// NewTypedProcessor creates a processor whose keywords carry a payload of type T.
+// It accepts the same options as NewKeywordProcessor.
+func NewTypedProcessor[T any](opts ...Option) *TypedProcessor[T] {
+	return &TypedProcessor[T]{kp: NewKeywordProcessor(opts...)}
+}
+
+// Add adds a keyword with its payload.
+// Adding the same keyword again replaces its payload.
+// Returns the processor for chaining.
+func (tp *TypedProcessor[T]) Add(keyword string, payload T) *TypedProcessor[T] {
+	return tp.AddWithCleanName(keyword, keyword, payload)
+}
+
+// AddWithCleanName adds a keyword with its clean name and payload.
+// Returns the processor for chaining.
+func (tp *TypedProcessor[T]) AddWithCleanName(keyword, cleanName string, payload T) *TypedProcessor[T] {
+	tp.kp.mu.Lock()
+	defer tp.kp.mu.Unlock()
+	tp.kp.setItem(keyword, cleanName, payload)
+	return tp
+}
+
+// Remove removes a keyword and its payload.
+// It reports whether the keyword was present.
+func (tp *TypedProcessor[T]) Remove(keyword string) bool {
+	return tp.kp.RemoveKeyword(keyword)
+}
+
+// Build compiles the keywords and their payloads.
+// Matching builds the processor automatically when keywords changed.
+func (tp *TypedProcessor[T]) Build() {
+	tp.kp.Build()
+}
+
+// ExtractKeywords searches for keywords in a string.
+// It returns a slice of the matches selected by the processor's MatchKind
+// together with their payloads.
+func (tp *TypedProcessor[T]) ExtractKeywords(sentence string) []TypedMatch[T] {
+	a := tp.kp.automaton()
+	matches := tp.extractKeywords(a, sentence)
+	tp.sort(a, matches)
+	return matches
+}
+
+// ExtractKeywordsFromBytes searches for keywords in a byte slice.
+// It returns a slice of the matches selected by the processor's MatchKind
+// together with their payloads.
+func (tp *TypedProcessor[T]) ExtractKeywordsFromBytes(sentence []byte) []TypedMatch[T] {
+	a := tp.kp.automaton()
+	matches := tp.extractKeywords(a, bytesToString(sentence))
+	detach(len(matches), func(i int) *Match { return &matches[i].Match }, sentence)
+	tp.sort(a, matches)
+	return matches
+}
+
+// extractKeywords returns the matches in sentence in the default order.
+func (tp *TypedProcessor[T]) extractKeywords(a *Automaton, sentence string) []TypedMatch[T] {
+	runes := utf8.RuneCountInString(sentence)
+	if runes == 0 {
+		return nil
+	}
+	matches := make([]TypedMatch[T], 0, a.capEstimate(runes))
+	a.search(sentence, a.matchKind, func(start, end, id int) bool {
+		payload, _ := a.entries[id].payload.(T)
+		matches = append(matches, TypedMatch[T]{
+			Match: Match{
+				start:     start,
+				end:       end,
+				match:     sentence[start:end],
+				cleanName: a.entries[id].cleanName,
+				id:        id,
+			},
+			payload: payload,
+		})
+		return true
+	})
+	a.stats.add(len(matches), runes)
+	return matches
+}
+
+// sort puts matches found in the default order into the automaton's MatchOrder.
+func (tp *TypedProcessor[T]) sort(a *Automaton, matches []TypedMatch[T]) {
+	if a.sorted() {
+		return
+	}
+	a.sortMatches(len(matches), func(i int) *Match { return &matches[i].Match }, func(i, j int) {
+		matches[i], matches[j] = matches[j], matches[i]
+	})
+}
+
+func (tp *TypedProcessor[T]) Close() {
+	tp.kp.Close()
+}
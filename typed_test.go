@@ -0,0 +1,32 @@
+package flashtext
+
+import "testing"
+
+type moderation struct {
+	category string
+	severity int
+}
+
+// 类型化附加数据测试
+func TestTypedProcessor(t *testing.T) {
+	tp := NewTypedProcessor[moderation]()
+	defer tp.Close()
+	tp.Add("spam", moderation{"ads", 1}).
+		Add("scam", moderation{"fraud", 3}).
+		Add("SPAM", moderation{"ads", 2}). // 不区分大小写时覆盖 "spam"
+		Build()
+
+	matches := tp.ExtractKeywords("Spam or SCAM?")
+	if len(matches) != 2 {
+		t.Fatalf("期望 2 个匹配, 实际 %d 个", len(matches))
+	}
+	expected := []moderation{{"ads", 2}, {"fraud", 3}}
+	for i, match := range matches {
+		if match.Payload() != expected[i] {
+			t.Errorf("第 %d 个匹配: 期望 %+v, 实际 %+v", i, expected[i], match.Payload())
+		}
+	}
+	if matches[1].MatchString() != "SCAM" || matches[1].CleanName() != "scam" {
+		t.Errorf("匹配文本或标准名称不正确: %s / %s", matches[1].MatchString(), matches[1].CleanName())
+	}
+}